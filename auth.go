@@ -0,0 +1,277 @@
+/**
+* Created by GoLand.
+* User: luffy
+* Date: 2026-10-19
+* Time: 11:20
+ */
+package greq
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+type APIKeyLocation int
+
+const (
+	APIKeyInHeader APIKeyLocation = iota
+	APIKeyInQuery
+)
+
+func basicAuth(username, password string) string {
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))
+}
+
+func (r *Request) SetBasicAuth(username, password string) {
+	r.SetHeader("Authorization", basicAuth(username, password))
+}
+
+func (r *Request) SetBearerToken(token string) {
+	r.SetHeader("Authorization", "Bearer "+token)
+}
+
+func (r *Request) SetAPIKey(name, value string, in APIKeyLocation) {
+	if in == APIKeyInQuery {
+		r.SetQuery(name, value)
+		return
+	}
+	r.SetHeader(name, value)
+}
+
+// SetDigestAuth answers HTTP Digest challenges for this request.
+func (r *Request) SetDigestAuth(username, password string) {
+	r.Use(DigestAuth(username, password))
+}
+
+func (c *Client) SetBasicAuth(username, password string) {
+	c.SetHeader("Authorization", basicAuth(username, password))
+}
+
+func (c *Client) SetBearerToken(token string) {
+	c.SetHeader("Authorization", "Bearer "+token)
+}
+
+func (c *Client) SetAPIKey(name, value string, in APIKeyLocation) {
+	if in == APIKeyInQuery {
+		c.SetQuery(name, value)
+		return
+	}
+	c.SetHeader(name, value)
+}
+
+// SetDigestAuth answers HTTP Digest challenges for every request of the
+// client. The last challenge is shared, so later requests authenticate
+// up front and count their nonce use.
+func (c *Client) SetDigestAuth(username, password string) {
+	c.Use(DigestAuth(username, password))
+}
+
+// DigestAuth returns a middleware implementing HTTP Digest authentication
+// (RFC 7616) with qop=auth and the MD5, SHA-256 and -sess algorithms. A 401
+// challenge is answered by sending the request again, which needs a
+// replayable body.
+func DigestAuth(username, password string) Middleware {
+	d := &digestAuth{username: username, password: password}
+	return d.middleware
+}
+
+type digestAuth struct {
+	username string
+	password string
+
+	mu        sync.Mutex
+	challenge *digestChallenge
+	nc        int
+}
+
+type digestChallenge struct {
+	realm     string
+	nonce     string
+	opaque    string
+	algorithm string
+	qop       string
+	stale     bool
+}
+
+func (d *digestAuth) middleware(next Handler) Handler {
+	return func(req *http.Request) (*http.Response, error) {
+		sent := d.authorize(req)
+		resp, err := next(req)
+		if err != nil || resp.StatusCode != http.StatusUnauthorized {
+			return resp, err
+		}
+		challenge := findDigestChallenge(resp.Header.Values("WWW-Authenticate"))
+		if challenge == nil || (sent && !challenge.stale && !d.isNewNonce(challenge)) {
+			return resp, err
+		}
+		if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+			return resp, err
+		}
+		d.mu.Lock()
+		d.challenge, d.nc = challenge, 0
+		d.mu.Unlock()
+
+		retry := req.Clone(req.Context())
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return resp, nil
+			}
+			retry.Body = body
+		}
+		_, _ = io.CopyN(ioutil.Discard, resp.Body, 4<<10)
+		resp.Body.Close()
+		d.authorize(retry)
+		return next(retry)
+	}
+}
+
+func (d *digestAuth) isNewNonce(challenge *digestChallenge) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.challenge == nil || d.challenge.nonce != challenge.nonce
+}
+
+// authorize sets the Authorization header from the last challenge, if any.
+func (d *digestAuth) authorize(req *http.Request) bool {
+	d.mu.Lock()
+	challenge := d.challenge
+	if challenge == nil {
+		d.mu.Unlock()
+		return false
+	}
+	d.nc++
+	nc := d.nc
+	d.mu.Unlock()
+	req.Header.Set("Authorization", d.credentials(challenge, req.Method, req.URL.RequestURI(), nc, newCnonce()))
+	return true
+}
+
+func (d *digestAuth) credentials(c *digestChallenge, method, uri string, nc int, cnonce string) string {
+	algorithm := strings.ToUpper(c.algorithm)
+	h := md5.New
+	if strings.HasPrefix(algorithm, "SHA-256") {
+		h = sha256.New
+	}
+	ha1 := hashHex(h, d.username+":"+c.realm+":"+d.password)
+	if strings.HasSuffix(algorithm, "-SESS") {
+		ha1 = hashHex(h, ha1+":"+c.nonce+":"+cnonce)
+	}
+	ha2 := hashHex(h, method+":"+uri)
+	ncValue := fmt.Sprintf("%08x", nc)
+
+	var response string
+	if c.qop != "" {
+		response = hashHex(h, strings.Join([]string{ha1, c.nonce, ncValue, cnonce, c.qop, ha2}, ":"))
+	} else {
+		response = hashHex(h, ha1+":"+c.nonce+":"+ha2)
+	}
+	params := []string{
+		fmt.Sprintf(`username="%s"`, d.username),
+		fmt.Sprintf(`realm="%s"`, c.realm),
+		fmt.Sprintf(`nonce="%s"`, c.nonce),
+		fmt.Sprintf(`uri="%s"`, uri),
+		fmt.Sprintf(`response="%s"`, response),
+	}
+	if c.algorithm != "" {
+		params = append(params, "algorithm="+c.algorithm)
+	}
+	if c.qop != "" {
+		params = append(params, "qop="+c.qop, "nc="+ncValue, fmt.Sprintf(`cnonce="%s"`, cnonce))
+	}
+	if c.opaque != "" {
+		params = append(params, fmt.Sprintf(`opaque="%s"`, c.opaque))
+	}
+	return "Digest " + strings.Join(params, ", ")
+}
+
+func findDigestChallenge(headers []string) *digestChallenge {
+	for _, header := range headers {
+		if len(header) < 7 || !strings.EqualFold(header[:7], "Digest ") {
+			continue
+		}
+		params := parseAuthParams(header[7:])
+		challenge := &digestChallenge{
+			realm:     params["realm"],
+			nonce:     params["nonce"],
+			opaque:    params["opaque"],
+			algorithm: params["algorithm"],
+			stale:     strings.EqualFold(params["stale"], "true"),
+		}
+		if qop, ok := params["qop"]; ok {
+			supported := false
+			for _, option := range strings.Split(qop, ",") {
+				if strings.TrimSpace(option) == "auth" {
+					supported = true
+				}
+			}
+			if !supported {
+				continue
+			}
+			challenge.qop = "auth"
+		}
+		return challenge
+	}
+	return nil
+}
+
+// parseAuthParams parses the comma separated key=value pairs of a
+// WWW-Authenticate challenge. Values may be quoted strings containing commas.
+func parseAuthParams(s string) map[string]string {
+	params := map[string]string{}
+	for {
+		s = strings.TrimLeft(s, " ,")
+		i := strings.IndexByte(s, '=')
+		if i < 0 {
+			return params
+		}
+		key := strings.ToLower(strings.TrimSpace(s[:i]))
+		s = strings.TrimLeft(s[i+1:], " ")
+		var value string
+		if strings.HasPrefix(s, `"`) {
+			var b strings.Builder
+			j := 1
+			for ; j < len(s) && s[j] != '"'; j++ {
+				if s[j] == '\\' && j+1 < len(s) {
+					j++
+				}
+				b.WriteByte(s[j])
+			}
+			value = b.String()
+			if j < len(s) {
+				j++
+			}
+			s = s[j:]
+		} else {
+			end := strings.IndexByte(s, ',')
+			if end < 0 {
+				end = len(s)
+			}
+			value = strings.TrimSpace(s[:end])
+			s = s[end:]
+		}
+		params[key] = value
+	}
+}
+
+func hashHex(h func() hash.Hash, s string) string {
+	sum := h()
+	sum.Write([]byte(s))
+	return hex.EncodeToString(sum.Sum(nil))
+}
+
+func newCnonce() string {
+	var buf [8]byte
+	_, _ = io.ReadFull(rand.Reader, buf[:])
+	return hex.EncodeToString(buf[:])
+}
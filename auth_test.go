@@ -0,0 +1,100 @@
+/**
+* Created by GoLand.
+* User: luffy
+* Date: 2026-10-19
+* Time: 12:10
+ */
+package greq
+
+import (
+	"crypto/md5"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestDigestCredentials(t *testing.T) {
+	// RFC 2617, section 3.5.
+	d := &digestAuth{username: "Mufasa", password: "Circle Of Life"}
+	challenge := &digestChallenge{
+		realm:  "testrealm@host.com",
+		nonce:  "dcd98b7102dd2f0e8b11d0f600bfb0c093",
+		opaque: "5ccc069c403ebaf9f0171e9517f40e41",
+		qop:    "auth",
+	}
+	auth := d.credentials(challenge, "GET", "/dir/index.html", 1, "0a4f113b")
+	if !strings.Contains(auth, `response="6629fae49393a05397450978507c4ef1"`) {
+		t.Errorf("digest credentials got = %s", auth)
+	}
+}
+
+func TestDigestAuth(t *testing.T) {
+	const nonce = "dcd98b7102dd2f0e8b11d0f600bfb0c093"
+	var counts []string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		params := parseAuthParams(strings.TrimPrefix(r.Header.Get("Authorization"), "Digest "))
+		ha1 := hashHex(md5.New, "luffy:greq:secret")
+		ha2 := hashHex(md5.New, r.Method+":"+params["uri"])
+		want := hashHex(md5.New, strings.Join([]string{ha1, nonce, params["nc"], params["cnonce"], "auth", ha2}, ":"))
+		if params["response"] != want || params["uri"] != r.URL.RequestURI() {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Digest realm="greq", qop="auth,auth-int", nonce="%s", opaque="xyz"`, nonce))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		counts = append(counts, params["nc"])
+		body, _ := ioutil.ReadAll(r.Body)
+		w.Write(body)
+	}
+	ts := server(handler)
+	defer ts.Close()
+
+	client := NewClient()
+	client.SetBaseURL(ts.URL)
+	client.SetDigestAuth("luffy", "secret")
+	for i := 0; i < 2; i++ {
+		req := client.NewRequest("post", "/dir/index.html")
+		req.SetQuery("i", fmt.Sprint(i))
+		req.SetBody("payload")
+		resp := req.Exec()
+		if resp.StatusCode() != http.StatusOK {
+			t.Fatalf("req.exec statuscode want = 200, got = %d", resp.StatusCode())
+		}
+		if body, _ := resp.ToString(); body != "payload" {
+			t.Errorf("got body = %s, want = payload", body)
+		}
+	}
+	if strings.Join(counts, ",") != "00000001,00000002" {
+		t.Errorf("nonce counts got = %v", counts)
+	}
+
+	req := NewRequest("get", ts.URL)
+	req.SetDigestAuth("luffy", "wrong")
+	if resp := req.Exec(); resp.StatusCode() != http.StatusUnauthorized {
+		t.Errorf("req.exec statuscode want = 401, got = %d", resp.StatusCode())
+	}
+}
+
+func TestBasicBearerAndAPIKey(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Header.Get("Authorization") + "|" + r.Header.Get("X-API-Key") + "|" + r.URL.Query().Get("api_key")))
+	}
+	ts := server(handler)
+	defer ts.Close()
+
+	req := NewRequest("get", ts.URL)
+	req.SetBasicAuth("luffy", "secret")
+	req.SetAPIKey("X-API-Key", "k1", APIKeyInHeader)
+	req.SetAPIKey("api_key", "k2", APIKeyInQuery)
+	if body, _ := req.Exec().ToString(); body != "Basic bHVmZnk6c2VjcmV0|k1|k2" {
+		t.Errorf("got body = %s", body)
+	}
+
+	client := NewClient()
+	client.SetBearerToken("token")
+	client.SetAPIKey("api_key", "k3", APIKeyInQuery)
+	if body, _ := client.NewRequest("get", ts.URL).Exec().ToString(); body != "Bearer token||k3" {
+		t.Errorf("got body = %s", body)
+	}
+}
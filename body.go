@@ -0,0 +1,140 @@
+/**
+* Created by GoLand.
+* User: luffy
+* Date: 2026-10-18
+* Time: 10:40
+ */
+package greq
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+var ErrBodyConsumed = errors.New("greq: request body has already been consumed")
+
+// payload keeps a request body in a form that can be sent more than once:
+// in-memory bytes, or a source opened again for every attempt. Any other
+// reader can be read only once; mu guards it, as clones of a request buffer
+// it concurrently.
+type payload struct {
+	mu     sync.Mutex
+	data   []byte
+	reader io.Reader
+	used   bool
+	source func() (io.ReadCloser, error)
+	size   int64
+	once   bool
+	// path is the file the source reads, used by ToCurl.
+	path string
+}
+
+func newPayload(body io.Reader) *payload {
+	switch v := body.(type) {
+	case nil:
+		return nil
+	case *bytes.Buffer:
+		return &payload{data: v.Bytes()}
+	case *bytes.Reader:
+		data := make([]byte, v.Len())
+		_, _ = v.ReadAt(data, v.Size()-int64(v.Len()))
+		return &payload{data: data}
+	case *strings.Reader:
+		data := make([]byte, v.Len())
+		_, _ = v.ReadAt(data, v.Size()-int64(v.Len()))
+		return &payload{data: data}
+	}
+	return &payload{reader: body}
+}
+
+// SetBodyFile sends the file at path, reopening it for every attempt and
+// redirect.
+func (r *Request) SetBodyFile(path string) {
+	info, err := os.Stat(path)
+	if err != nil {
+		r.err = err
+		return
+	}
+	r.SetBodyFunc(func() (io.ReadCloser, error) {
+		return os.Open(path)
+	}, info.Size())
+	r.body.path = path
+}
+
+// SetBodyFunc sends the body returned by fn, which is called again for every
+// attempt and redirect. size is the content length, or -1 when unknown.
+func (r *Request) SetBodyFunc(fn func() (io.ReadCloser, error), size int64) {
+	r.body = &payload{source: fn, size: size}
+}
+
+// copy returns a payload sending the same body. A one-shot reader is read
+// into memory first, so both payloads can send it.
+func (p *payload) copy() (*payload, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.reader != nil && !p.used {
+		data, err := ioutil.ReadAll(p.reader)
+		if err != nil {
+			return nil, err
+		}
+		p.data, p.reader = data, nil
+	}
+	return &payload{
+		data:   p.data,
+		reader: p.reader,
+		used:   p.used,
+		source: p.source,
+		size:   p.size,
+		once:   p.once,
+		path:   p.path,
+	}, nil
+}
+
+func (p *payload) open() (io.Reader, error) {
+	if p == nil {
+		return nil, nil
+	}
+	if p.source != nil {
+		return p.source()
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.reader == nil {
+		return bytes.NewReader(p.data), nil
+	}
+	if p.used {
+		return nil, ErrBodyConsumed
+	}
+	p.used = true
+	return p.reader, nil
+}
+
+// newHTTPRequest creates the request for one execution of p. Bytes get their
+// ContentLength and GetBody from http.NewRequest; sources set them here, so
+// 307/308 redirects and retries can send the body again.
+func (p *payload) newHTTPRequest(method, target string) (*http.Request, error) {
+	body, err := p.open()
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(method, target, body)
+	if err != nil {
+		if closer, ok := body.(io.Closer); ok {
+			closer.Close()
+		}
+		return nil, err
+	}
+	if p != nil && p.source != nil {
+		req.ContentLength = p.size
+		if !p.once {
+			req.GetBody = p.source
+		}
+	}
+	return req, nil
+}
@@ -0,0 +1,72 @@
+/**
+* Created by GoLand.
+* User: luffy
+* Date: 2026-10-18
+* Time: 22:20
+ */
+package greq
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReplayableBodyRedirect(t *testing.T) {
+	dir, err := ioutil.TempDir("", "greq")
+	if err != nil {
+		t.Fatalf("ioutil.TempDir error, err = %s", err.Error())
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "body.txt")
+	if err := ioutil.WriteFile(path, []byte("from file"), 0644); err != nil {
+		t.Fatalf("ioutil.WriteFile error, err = %s", err.Error())
+	}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/old" {
+			http.Redirect(w, r, "/new", http.StatusPermanentRedirect)
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		if r.ContentLength != int64(len(body)) {
+			t.Errorf("content length got = %d, want = %d", r.ContentLength, len(body))
+		}
+		w.Write(body)
+	}
+	ts := server(handler)
+	defer ts.Close()
+
+	for _, tc := range []struct {
+		name string
+		set  func(req *Request)
+		want string
+	}{
+		{"bytes", func(req *Request) { req.SetBody([]byte("from bytes")) }, "from bytes"},
+		{"string", func(req *Request) { req.SetBody("from string") }, "from string"},
+		{"file", func(req *Request) { req.SetBodyFile(path) }, "from file"},
+		{"func", func(req *Request) {
+			req.SetBodyFunc(func() (io.ReadCloser, error) {
+				return ioutil.NopCloser(strings.NewReader("from func")), nil
+			}, 9)
+		}, "from func"},
+	} {
+		req := NewRequest("post", ts.URL+"/old")
+		tc.set(req)
+		resp := req.Exec()
+		body, err := resp.ToString()
+		if err != nil {
+			t.Fatalf("%s: req.exec error err= %s", tc.name, err.Error())
+		}
+		if body != tc.want {
+			t.Errorf("%s: got body = %s, want = %s", tc.name, body, tc.want)
+		}
+		if resp.Request().GetBody == nil {
+			t.Errorf("%s: GetBody is nil", tc.name)
+		}
+	}
+}
@@ -0,0 +1,230 @@
+/**
+* Created by GoLand.
+* User: luffy
+* Date: 2026-10-19
+* Time: 18:20
+ */
+package greq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type CircuitState int
+
+const (
+	CircuitClosed CircuitState = iota
+	CircuitOpen
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return fmt.Sprintf("CircuitState(%d)", int(s))
+}
+
+var ErrCircuitOpen = errors.New("greq: circuit breaker is open")
+
+// CircuitOpenError is returned without sending the request while the circuit
+// of Key is open. It matches ErrCircuitOpen with errors.Is.
+type CircuitOpenError struct {
+	Key   string
+	Until time.Time
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("greq: circuit breaker for %s is open until %s", e.Key, e.Until.Format(time.RFC3339))
+}
+
+func (e *CircuitOpenError) Is(target error) bool {
+	return target == ErrCircuitOpen
+}
+
+// CircuitBreaker stops sending requests to an upstream that keeps failing.
+// FailureThreshold consecutive failures (transport errors or a status in
+// FailureStatus) open the circuit of the request's key. After OpenTimeout it
+// turns half-open and lets HalfOpenRequests probes through: if they all
+// succeed it closes again, and any failure opens it for another OpenTimeout.
+// Attempts cancelled through their context are not counted either way.
+type CircuitBreaker struct {
+	FailureThreshold int
+	OpenTimeout      time.Duration
+	HalfOpenRequests int
+	FailureStatus    []int
+	// KeyFunc selects the circuit of a request; the URL host by default.
+	KeyFunc       func(req *http.Request) string
+	OnStateChange func(key string, from, to CircuitState)
+
+	mu       sync.Mutex
+	circuits map[string]*circuit
+}
+
+type circuit struct {
+	state     CircuitState
+	failures  int
+	successes int
+	probes    int
+	until     time.Time
+}
+
+func NewCircuitBreaker(failureThreshold int, openTimeout time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		FailureThreshold: failureThreshold,
+		OpenTimeout:      openTimeout,
+		HalfOpenRequests: 1,
+		FailureStatus: []int{
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+// State returns the current state of the circuit of key.
+func (b *CircuitBreaker) State(key string) CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c, ok := b.circuits[key]
+	if !ok {
+		return CircuitClosed
+	}
+	if c.state == CircuitOpen && !time.Now().Before(c.until) {
+		return CircuitHalfOpen
+	}
+	return c.state
+}
+
+// Middleware returns the breaker as a middleware. Every attempt counts, so
+// retries of a failing request push the circuit towards open.
+func (b *CircuitBreaker) Middleware() Middleware {
+	return func(next Handler) Handler {
+		return func(req *http.Request) (*http.Response, error) {
+			key := req.URL.Host
+			if b.KeyFunc != nil {
+				key = b.KeyFunc(req)
+			}
+			if err := b.allow(key); err != nil {
+				return nil, err
+			}
+			resp, err := next(req)
+			b.done(key, b.outcome(resp, err))
+			return resp, err
+		}
+	}
+}
+
+type outcome int
+
+const (
+	succeeded outcome = iota
+	failed
+	// cancelled attempts say nothing about the upstream and are not counted.
+	cancelled
+)
+
+func (b *CircuitBreaker) outcome(resp *http.Response, err error) outcome {
+	if errors.Is(err, context.Canceled) {
+		return cancelled
+	}
+	if err != nil {
+		return failed
+	}
+	for _, code := range b.FailureStatus {
+		if resp.StatusCode == code {
+			return failed
+		}
+	}
+	return succeeded
+}
+
+func (b *CircuitBreaker) allow(key string) error {
+	b.mu.Lock()
+	if b.circuits == nil {
+		b.circuits = map[string]*circuit{}
+	}
+	c, ok := b.circuits[key]
+	if !ok {
+		c = &circuit{}
+		b.circuits[key] = c
+	}
+	from := c.state
+	if c.state == CircuitOpen && !time.Now().Before(c.until) {
+		c.state, c.probes, c.successes = CircuitHalfOpen, 0, 0
+	}
+	var err error
+	switch {
+	case c.state == CircuitOpen:
+		err = &CircuitOpenError{Key: key, Until: c.until}
+	case c.state == CircuitHalfOpen && c.probes >= b.halfOpenRequests():
+		err = &CircuitOpenError{Key: key, Until: time.Now()}
+	case c.state == CircuitHalfOpen:
+		c.probes++
+	}
+	to := c.state
+	b.mu.Unlock()
+	b.changed(key, from, to)
+	return err
+}
+
+func (b *CircuitBreaker) done(key string, result outcome) {
+	b.mu.Lock()
+	c := b.circuits[key]
+	from := c.state
+	switch {
+	case c.state == CircuitHalfOpen && result == cancelled:
+		// Free the probe slot for another request.
+		c.probes--
+	case c.state == CircuitHalfOpen && result == failed:
+		b.open(c)
+	case c.state == CircuitHalfOpen:
+		c.successes++
+		if c.successes >= b.halfOpenRequests() {
+			c.state, c.failures = CircuitClosed, 0
+		}
+	case c.state == CircuitClosed && result == failed:
+		c.failures++
+		if c.failures >= b.FailureThreshold {
+			b.open(c)
+		}
+	case c.state == CircuitClosed && result == succeeded:
+		c.failures = 0
+	}
+	to := c.state
+	b.mu.Unlock()
+	b.changed(key, from, to)
+}
+
+func (b *CircuitBreaker) open(c *circuit) {
+	c.state, c.failures = CircuitOpen, 0
+	c.until = time.Now().Add(b.OpenTimeout)
+}
+
+func (b *CircuitBreaker) halfOpenRequests() int {
+	if b.HalfOpenRequests < 1 {
+		return 1
+	}
+	return b.HalfOpenRequests
+}
+
+func (b *CircuitBreaker) changed(key string, from, to CircuitState) {
+	if from != to && b.OnStateChange != nil {
+		b.OnStateChange(key, from, to)
+	}
+}
+
+func (c *Client) SetCircuitBreaker(breaker *CircuitBreaker) {
+	c.Use(breaker.Middleware())
+}
@@ -0,0 +1,109 @@
+/**
+* Created by GoLand.
+* User: luffy
+* Date: 2026-10-19
+* Time: 18:50
+ */
+package greq
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	var status, hits int32 = http.StatusServiceUnavailable, 0
+	ts := server(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.WriteHeader(int(atomic.LoadInt32(&status)))
+	})
+	defer ts.Close()
+	host, _ := url.Parse(ts.URL)
+
+	var changes []string
+	breaker := NewCircuitBreaker(3, 50*time.Millisecond)
+	breaker.OnStateChange = func(key string, from, to CircuitState) {
+		changes = append(changes, from.String()+">"+to.String())
+	}
+	client := NewClient()
+	client.SetBaseURL(ts.URL)
+	client.SetCircuitBreaker(breaker)
+	for i := 0; i < 3; i++ {
+		client.NewRequest("get", "/").Exec()
+	}
+	if state := breaker.State(host.Host); state != CircuitOpen {
+		t.Fatalf("state got = %s, want = open", state)
+	}
+	err := client.NewRequest("get", "/").Exec().Error()
+	var openErr *CircuitOpenError
+	if !errors.As(err, &openErr) || !errors.Is(err, ErrCircuitOpen) || openErr.Key != host.Host {
+		t.Errorf("req.exec error got = %v, want *CircuitOpenError", err)
+	}
+	if n := atomic.LoadInt32(&hits); n != 3 {
+		t.Errorf("server hits got = %d, want = 3", n)
+	}
+
+	// The probe fails and opens the circuit again.
+	time.Sleep(60 * time.Millisecond)
+	client.NewRequest("get", "/").Exec()
+	time.Sleep(60 * time.Millisecond)
+	atomic.StoreInt32(&status, http.StatusOK)
+	if resp := client.NewRequest("get", "/").Exec(); resp.StatusCode() != http.StatusOK {
+		t.Errorf("req.exec statuscode want = 200, got = %d", resp.StatusCode())
+	}
+	want := "closed>open,open>half-open,half-open>open,open>half-open,half-open>closed"
+	if got := strings.Join(changes, ","); got != want {
+		t.Errorf("state changes got = %s, want = %s", got, want)
+	}
+}
+
+func TestCircuitBreakerCancelledProbe(t *testing.T) {
+	var hits int32
+	ts := server(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&hits, 1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	})
+	defer ts.Close()
+	host, _ := url.Parse(ts.URL)
+
+	breaker := NewCircuitBreaker(1, 20*time.Millisecond)
+	var probes int32
+	client := NewClient()
+	client.SetBaseURL(ts.URL)
+	client.SetCircuitBreaker(breaker)
+	client.Use(func(next Handler) Handler {
+		return func(req *http.Request) (*http.Response, error) {
+			atomic.AddInt32(&probes, 1)
+			return next(req)
+		}
+	})
+	client.NewRequest("get", "/").Exec()
+	time.Sleep(30 * time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req := client.NewRequest("get", "/")
+	req.SetContext(ctx)
+	if err := req.Exec().Error(); !errors.Is(err, context.Canceled) {
+		t.Fatalf("req.exec error got = %v, want = context.Canceled", err)
+	}
+	if n := atomic.LoadInt32(&probes); n != 2 {
+		t.Fatalf("attempts got = %d, want = 2", n)
+	}
+	if state := breaker.State(host.Host); state != CircuitHalfOpen {
+		t.Errorf("state after a cancelled probe got = %s, want = half-open", state)
+	}
+	if resp := client.NewRequest("get", "/").Exec(); resp.StatusCode() != http.StatusOK {
+		t.Errorf("probe statuscode want = 200, got = %d", resp.StatusCode())
+	}
+	if state := breaker.State(host.Host); state != CircuitClosed {
+		t.Errorf("state got = %s, want = closed", state)
+	}
+}
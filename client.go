@@ -0,0 +1,184 @@
+/**
+* Created by GoLand.
+* User: luffy
+* Date: 2026-10-18
+* Time: 09:30
+ */
+package greq
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// Client holds the transport, cookie jar and defaults shared by the requests
+// it creates, so connections are pooled across calls. A Client is safe for
+// concurrent use; configure it before sending requests through it.
+type Client struct {
+	mu      sync.RWMutex
+	client  *http.Client
+	baseURL string
+	header  http.Header
+	query   url.Values
+	retry   *RetryPolicy
+	expect  *statusPolicy
+
+	pathParams  map[string]string
+	middlewares []Middleware
+	signer      Signer
+	limits      *rateLimits
+	metrics     MetricsSink
+	logging     *LogPolicy
+}
+
+func NewClient() *Client {
+	return &Client{
+		client: newHttpClient(),
+		header: http.Header{},
+	}
+}
+
+func newHttpClient() *http.Client {
+	jar, _ := cookiejar.New(nil)
+	transport := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: false},
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+			DualStack: true,
+		}).DialContext,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+	return &http.Client{
+		Jar:       jar,
+		Transport: transport,
+		Timeout:   30 * time.Second,
+	}
+}
+
+func (c *Client) NewRequest(method, path string) *Request {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	req := newRequest(method, path)
+	req.baseURL = c.baseURL
+	for key, value := range c.pathParams {
+		req.SetPathParam(key, value)
+	}
+	for key, values := range c.header {
+		req.header[key] = append([]string(nil), values...)
+	}
+	req.query = cloneValues(c.query)
+	req.retry = c.retry
+	req.expect = c.expect.clone()
+	req.middlewares = append([]Middleware(nil), c.middlewares...)
+	req.signer = c.signer
+	req.limits = c.limits
+	req.metrics = c.metrics
+	req.logging = c.logging
+	req.client = c.client
+	return req
+}
+
+func (c *Client) SetHttpClient(client *http.Client) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.client = client
+}
+
+func (c *Client) GetHttpClient() *http.Client {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.client
+}
+
+func (c *Client) SetBaseURL(baseURL string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.baseURL = baseURL
+}
+
+func (c *Client) BaseURL() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.baseURL
+}
+
+func (c *Client) SetPathParam(key, value string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.pathParams == nil {
+		c.pathParams = map[string]string{}
+	}
+	c.pathParams[key] = value
+}
+
+func (c *Client) SetHeader(key, value string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.header.Set(key, value)
+}
+
+func (c *Client) AddHeader(key, value string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.header.Add(key, value)
+}
+
+func (c *Client) SetQuery(key, value string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.query == nil {
+		c.query = url.Values{}
+	}
+	c.query.Set(key, value)
+}
+
+func (c *Client) AddQuery(key, value string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.query == nil {
+		c.query = url.Values{}
+	}
+	c.query.Add(key, value)
+}
+
+func (c *Client) SetTimeout(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.client.Timeout = d
+}
+
+func (c *Client) SetProxy(proxyURL string) error {
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if transport, ok := c.client.Transport.(*http.Transport); ok {
+		transport.Proxy = http.ProxyURL(u)
+	}
+	return nil
+}
+
+func (c *Client) EnableInsecureTLS(enable bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	transport, ok := c.client.Transport.(*http.Transport)
+	if !ok {
+		return
+	}
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{}
+	}
+	transport.TLSClientConfig.InsecureSkipVerify = enable
+}
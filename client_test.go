@@ -0,0 +1,84 @@
+/**
+* Created by GoLand.
+* User: luffy
+* Date: 2026-10-18
+* Time: 10:05
+ */
+package greq
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClientSharesConnections(t *testing.T) {
+	var conns int32
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/users" {
+			t.Errorf("path got = %s, want = /api/users", r.URL.Path)
+		}
+		if v := r.Header.Get("X-Client"); v != "greq" {
+			t.Errorf("header X-Client got = %s, want = greq", v)
+		}
+		w.Write([]byte("ok"))
+	}))
+	ts.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&conns, 1)
+		}
+	}
+	ts.Start()
+	defer ts.Close()
+
+	client := NewClient()
+	client.SetBaseURL(ts.URL + "/api/")
+	client.SetHeader("X-Client", "greq")
+	for i := 0; i < 5; i++ {
+		resp := client.NewRequest("get", "/users").Exec()
+		if err := resp.Error(); err != nil {
+			t.Fatalf("req.exec error err= %s", err.Error())
+		}
+		if _, err := resp.ToBytes(); err != nil {
+			t.Fatalf("resp.ToBytes error, err = %s", err.Error())
+		}
+	}
+	if n := atomic.LoadInt32(&conns); n != 1 {
+		t.Errorf("connections got = %d, want = 1", n)
+	}
+}
+
+func TestClientConcurrentRequests(t *testing.T) {
+	ts := server(nil)
+	defer ts.Close()
+
+	client := NewClient()
+	client.SetBaseURL(ts.URL)
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			req := client.NewRequest("get", "/")
+			if i%2 == 0 {
+				req.SetTimeout(5 * time.Second)
+			}
+			resp := req.Exec()
+			if err := resp.Error(); err != nil {
+				t.Errorf("req.exec error err= %s", err.Error())
+				return
+			}
+			if resp.StatusCode() != 200 {
+				t.Errorf("req.exec statuscode want = 200, got = %d", resp.StatusCode())
+			}
+		}(i)
+	}
+	wg.Wait()
+	if timeout := client.GetHttpClient().Timeout; timeout != 30*time.Second {
+		t.Errorf("client timeout got = %s, want = 30s", timeout)
+	}
+}
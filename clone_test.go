@@ -0,0 +1,195 @@
+/**
+* Created by GoLand.
+* User: luffy
+* Date: 2026-10-18
+* Time: 21:40
+ */
+package greq
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"testing/iotest"
+	"time"
+)
+
+func TestRequestTemplate(t *testing.T) {
+	ts := server(func(w http.ResponseWriter, r *http.Request) {
+		if v := r.URL.RawQuery; v != "a=1&foo=bar" {
+			t.Errorf("query got = %s, want = a=1&foo=bar", v)
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		w.Write(body)
+	})
+	defer ts.Close()
+
+	template := NewRequest("post", ts.URL+"?a=1")
+	template.SetParam("foo", "bar")
+	template.SetBody(`{"code":"0"}`)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			body, err := template.Exec().ToString()
+			if err != nil {
+				t.Errorf("req.exec error err= %s", err.Error())
+			}
+			if body != `{"code":"0"}` {
+				t.Errorf("got body = %s", body)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestRequestClone(t *testing.T) {
+	ts := server(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		w.Write([]byte(r.Header.Get("X-Name") + ":" + r.URL.Query().Get("name") + ":" + string(body)))
+	})
+	defer ts.Close()
+
+	req := NewRequest("put", ts.URL)
+	req.SetHeader("X-Name", "origin")
+	req.SetParam("name", "origin")
+	req.SetBody(iotest.OneByteReader(strings.NewReader("payload")))
+	clone := req.Clone()
+	clone.SetHeader("X-Name", "clone")
+	clone.SetParam("name", "clone")
+
+	for _, tc := range []struct {
+		req  *Request
+		want string
+	}{
+		{req, "origin:origin:payload"},
+		{clone, "clone:clone:payload"},
+		{clone, "clone:clone:payload"},
+	} {
+		body, err := tc.req.Exec().ToString()
+		if err != nil {
+			t.Fatalf("req.exec error err= %s", err.Error())
+		}
+		if body != tc.want {
+			t.Errorf("got body = %s, want = %s", body, tc.want)
+		}
+	}
+}
+
+func TestCloneFileReader(t *testing.T) {
+	ts := server(func(w http.ResponseWriter, r *http.Request) {
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			t.Errorf("r.FormFile error, err = %s", err.Error())
+			return
+		}
+		body, _ := ioutil.ReadAll(file)
+		w.Write(body)
+	})
+	defer ts.Close()
+
+	req := NewRequest("post", ts.URL)
+	req.AddFileReader("file", "a.txt", "text/plain", strings.NewReader("content"))
+	clone := req.Clone()
+	if err := clone.Exec().Error(); err == nil || !strings.Contains(err.Error(), `"file"`) {
+		t.Errorf("clone.exec error got = %v, want an error for the reader part", err)
+	}
+	// The original still sends its file.
+	body, err := req.Exec().ToString()
+	if err != nil || body != "content" {
+		t.Errorf("req.exec got = %s, %v", body, err)
+	}
+}
+
+func TestConcurrentClone(t *testing.T) {
+	type apiError struct {
+		ID string `json:"id"`
+	}
+	ts := server(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if string(body) != "payload" {
+			t.Errorf("got body = %s, want = payload", body)
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"id":"` + r.Header.Get("X-ID") + `"}`))
+	})
+	defer ts.Close()
+
+	template := NewRequest("post", ts.URL)
+	template.SetTimeout(5 * time.Second)
+	template.SetBody(iotest.OneByteReader(strings.NewReader("payload")))
+	template.ExpectStatus()
+	template.SetErrorResult(&apiError{})
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			req := template.Clone()
+			req.SetHeader("X-ID", id)
+			req.SetTimeout(time.Second)
+			var httpErr *HTTPError
+			if err := req.Exec().Error(); !errors.As(err, &httpErr) {
+				t.Errorf("req.exec error got = %v, want *HTTPError", err)
+				return
+			}
+			if result := httpErr.Result.(*apiError); result.ID != id {
+				t.Errorf("error result got = %s, want = %s", result.ID, id)
+			}
+		}(strconv.Itoa(i))
+	}
+	wg.Wait()
+}
+
+func TestRequestTemplateErrorResult(t *testing.T) {
+	type apiError struct {
+		ID   string `json:"id"`
+		Note string `json:"note"`
+	}
+	ts := server(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		if id := r.Header.Get("X-ID"); id == "0" {
+			w.Write([]byte(`{"id":"0","note":"first"}`))
+		} else {
+			w.Write([]byte(`{"id":"` + id + `"}`))
+		}
+	})
+	defer ts.Close()
+
+	template := NewRequest("get", ts.URL)
+	template.SetHeader("X-ID", "0")
+	template.SetErrorResult(&apiError{})
+	var httpErr *HTTPError
+	if err := template.Exec().Error(); !errors.As(err, &httpErr) {
+		t.Fatalf("req.exec error got = %v, want *HTTPError", err)
+	}
+	template.SetHeader("X-ID", "1")
+	if err := template.Exec().Error(); !errors.As(err, &httpErr) {
+		t.Fatalf("req.exec error got = %v, want *HTTPError", err)
+	}
+	if result := httpErr.Result.(*apiError); result.ID != "1" || result.Note != "" {
+		t.Errorf("error result got = %+v, want a fresh value", result)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var httpErr *HTTPError
+			if err := template.Exec().Error(); !errors.As(err, &httpErr) {
+				t.Errorf("req.exec error got = %v, want *HTTPError", err)
+				return
+			}
+			if result := httpErr.Result.(*apiError); result.ID != "1" {
+				t.Errorf("error result got = %+v", result)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,119 @@
+/**
+* Created by GoLand.
+* User: luffy
+* Date: 2026-10-18
+* Time: 19:30
+ */
+package greq
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"mime"
+	"strings"
+	"sync"
+)
+
+// Codec encodes request bodies and decodes response bodies of one content
+// type.
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+var codecs = struct {
+	sync.RWMutex
+	m map[string]Codec
+}{m: map[string]Codec{}}
+
+func init() {
+	RegisterCodec("application/json", jsonCodec{})
+	RegisterCodec("application/xml", xmlCodec{})
+	RegisterCodec("text/xml", xmlCodec{})
+}
+
+// RegisterCodec registers codec for the media type of contentType, replacing
+// any codec registered before. Parameters such as charset are ignored.
+func RegisterCodec(contentType string, codec Codec) {
+	codecs.Lock()
+	defer codecs.Unlock()
+	codecs.m[mediaType(contentType)] = codec
+}
+
+// LookupCodec returns the codec registered for contentType. Structured syntax
+// suffixes fall back to their base type, so application/problem+json uses the
+// application/json codec.
+func LookupCodec(contentType string) (Codec, bool) {
+	media := mediaType(contentType)
+	codecs.RLock()
+	defer codecs.RUnlock()
+	if codec, ok := codecs.m[media]; ok {
+		return codec, true
+	}
+	if i := strings.LastIndexByte(media, '+'); i >= 0 {
+		codec, ok := codecs.m["application/"+media[i+1:]]
+		return codec, ok
+	}
+	return nil, false
+}
+
+func mediaType(contentType string) string {
+	media, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(contentType))
+	}
+	return media
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	return d.Decode(v)
+}
+
+type xmlCodec struct{}
+
+func (xmlCodec) Marshal(v interface{}) ([]byte, error) {
+	return xml.Marshal(v)
+}
+
+func (xmlCodec) Unmarshal(data []byte, v interface{}) error {
+	return xml.Unmarshal(data, v)
+}
+
+// encodeBody marshals v with the codec registered for contentType and uses
+// it as the request body.
+func (r *Request) encodeBody(contentType string, v interface{}) {
+	codec, ok := LookupCodec(contentType)
+	if !ok {
+		contentType = TypeJSON
+		codec = jsonCodec{}
+	}
+	data, err := codec.Marshal(v)
+	if err != nil {
+		r.err = err
+	}
+	r.body = &payload{data: data}
+	r.SetContentType(contentType)
+}
+
+// Decode decodes the response body with the codec registered for its
+// Content-Type, falling back to JSON.
+func (r *Response) Decode(v interface{}) error {
+	b, err := r.ToBytes()
+	if err != nil {
+		return err
+	}
+	codec, ok := LookupCodec(r.Header().Get("Content-Type"))
+	if !ok {
+		codec, _ = LookupCodec(TypeJSON)
+	}
+	return codec.Unmarshal(b, v)
+}
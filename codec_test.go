@@ -0,0 +1,86 @@
+/**
+* Created by GoLand.
+* User: luffy
+* Date: 2026-10-18
+* Time: 20:05
+ */
+package greq
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// pairCodec encodes a content as "code=msg".
+type pairCodec struct{}
+
+func (pairCodec) Marshal(v interface{}) ([]byte, error) {
+	c, ok := v.(content)
+	if !ok {
+		return nil, errors.New("pairCodec: unsupported type")
+	}
+	return []byte(c.Code + "=" + c.Msg), nil
+}
+
+func (pairCodec) Unmarshal(data []byte, v interface{}) error {
+	c, ok := v.(*content)
+	if !ok {
+		return errors.New("pairCodec: unsupported type")
+	}
+	parts := strings.SplitN(string(data), "=", 2)
+	c.Code, c.Msg = parts[0], parts[1]
+	return nil
+}
+
+func TestCodecRegistry(t *testing.T) {
+	const typePair = "application/x-pair"
+	RegisterCodec(typePair, pairCodec{})
+	ts := server(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		w.Header().Set("Content-Type", r.Header.Get("Content-Type"))
+		w.Write(body)
+	})
+	defer ts.Close()
+
+	req := NewRequest("post", ts.URL)
+	req.SetContentType(typePair + "; charset=utf-8")
+	req.SetBody(content{Code: "0", Msg: "pair"})
+	resp := req.Exec()
+	if body, _ := resp.ToString(); body != "0=pair" {
+		t.Errorf("got body = %s, want = 0=pair", body)
+	}
+	var c content
+	if err := resp.Decode(&c); err != nil {
+		t.Fatalf("resp.Decode error, err = %s", err.Error())
+	}
+	if c != (content{Code: "0", Msg: "pair"}) {
+		t.Errorf("got content = %+v", c)
+	}
+
+	req = NewRequest("post", ts.URL)
+	req.SetBody(content{Code: "0", Msg: "json"})
+	resp = req.Exec()
+	if v := resp.Header().Get("Content-Type"); v != TypeJSON {
+		t.Errorf("Content-Type got = %s, want = %s", v, TypeJSON)
+	}
+	c = content{}
+	if err := resp.Decode(&c); err != nil || c.Msg != "json" {
+		t.Errorf("resp.Decode got = %+v, err = %v", c, err)
+	}
+}
+
+func TestLookupCodecSuffix(t *testing.T) {
+	codec, ok := LookupCodec("application/problem+json; charset=utf-8")
+	if !ok {
+		t.Fatalf("LookupCodec application/problem+json got no codec")
+	}
+	if _, isJSON := codec.(jsonCodec); !isJSON {
+		t.Errorf("LookupCodec got = %T, want jsonCodec", codec)
+	}
+	if _, ok := LookupCodec("application/x-unknown"); ok {
+		t.Errorf("LookupCodec application/x-unknown got a codec")
+	}
+}
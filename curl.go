@@ -0,0 +1,211 @@
+/**
+* Created by GoLand.
+* User: luffy
+* Date: 2026-10-20
+* Time: 10:00
+ */
+package greq
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ToCurl returns a curl command that sends the request as Exec would. Files
+// set with SetBodyFile or attached with a Path are referenced instead of
+// inlined; bodies only available as a reader are read from stdin (@-).
+func (r *Request) ToCurl() (string, error) {
+	target, header, body, multipart, err := r.prepare()
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequest(r.method, target, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header = header
+	for _, cookie := range r.cookies {
+		req.AddCookie(cookie)
+	}
+	return curlCommand(r.GetClient(), req, body.curlArgs(), multipart), nil
+}
+
+// CurlCommand returns a curl command for the request as it was sent, with
+// the headers set by middlewares on its first attempt. A body read from a
+// one-shot reader is inlined when it was sent completely and is at most
+// MaxCurlBodySize bytes.
+func (r *Response) CurlCommand() string {
+	if r.req == nil || r.client == nil {
+		return ""
+	}
+	args := r.body.curlArgs()
+	if data, ok := r.sent.bytes(); ok {
+		args = []string{"--data-raw", shellQuote(string(data))}
+	}
+	return curlCommand(r.client, r.req, args, r.multipart)
+}
+
+func curlCommand(client *http.Client, req *http.Request, bodyArgs []string, multipart *multipartBody) string {
+	args := []string{"curl"}
+	switch {
+	case req.Method == http.MethodHead:
+		// -X HEAD would make curl wait for a body.
+		args = append(args, "-I")
+	case req.Method != http.MethodGet || multipart != nil || len(bodyArgs) > 0:
+		args = append(args, "-X", req.Method)
+	}
+	args = append(args, shellQuote(req.URL.String()))
+
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if multipart != nil && name == "Content-Type" {
+			// curl writes its own boundary.
+			continue
+		}
+		for _, value := range req.Header[name] {
+			if name == "Cookie" {
+				args = append(args, "-b", shellQuote(value))
+			} else {
+				args = append(args, "-H", shellQuote(name+": "+value))
+			}
+		}
+	}
+
+	if multipart != nil {
+		args = append(args, multipart.curlArgs()...)
+	} else {
+		args = append(args, bodyArgs...)
+	}
+
+	if transport, ok := client.Transport.(*http.Transport); ok {
+		if transport.Proxy != nil {
+			if proxy, err := transport.Proxy(req); err == nil && proxy != nil {
+				args = append(args, "-x", shellQuote(proxy.String()))
+			}
+		}
+		if transport.TLSClientConfig != nil && transport.TLSClientConfig.InsecureSkipVerify {
+			args = append(args, "-k")
+		}
+	}
+	return strings.Join(args, " ")
+}
+
+func (p *payload) curlArgs() []string {
+	if p == nil {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	switch {
+	case p.path != "":
+		return []string{"--data-binary", shellQuote("@" + p.path)}
+	case p.source != nil || p.reader != nil:
+		return []string{"--data-binary", "@-"}
+	case len(p.data) > 0:
+		// --data-raw, since curl reads a --data-binary value starting with @
+		// from a file.
+		return []string{"--data-raw", shellQuote(string(p.data))}
+	}
+	return nil
+}
+
+func (m *multipartBody) curlArgs() []string {
+	var args []string
+	keys := make([]string, 0, len(m.fields))
+	for key := range m.fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		for _, value := range m.fields[key] {
+			args = append(args, "--form-string", shellQuote(key+"="+value))
+		}
+	}
+	for _, part := range m.files {
+		source := "@-"
+		if part.Reader == nil {
+			source = "@" + part.Path
+		}
+		form := part.FieldName + "=" + source
+		if name := part.FileName; name != "" && (part.Reader != nil || name != filepath.Base(part.Path)) {
+			form += `;filename="` + escapeQuotes(name) + `"`
+		}
+		if part.ContentType != "" {
+			form += `;type="` + escapeQuotes(part.ContentType) + `"`
+		}
+		args = append(args, "-F", shellQuote(form))
+	}
+	return args
+}
+
+// shellQuote quotes s for a POSIX shell, leaving plain words as they are.
+func shellQuote(s string) string {
+	if s != "" && strings.IndexFunc(s, func(c rune) bool {
+		return !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || strings.ContainsRune("@%+=:,./-_", c))
+	}) < 0 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// MaxCurlBodySize limits how much of a one-shot request body a Response
+// keeps for CurlCommand.
+var MaxCurlBodySize = 64 << 10
+
+// captureBody keeps a copy of a one-shot request body as it is sent, since
+// it cannot be read again afterwards.
+func (r *Response) captureBody() {
+	req := r.req
+	if r.multipart != nil || req.GetBody != nil || req.Body == nil || req.Body == http.NoBody {
+		return
+	}
+	r.sent = &sentBody{ReadCloser: req.Body}
+	req.Body = r.sent
+}
+
+type sentBody struct {
+	io.ReadCloser
+	mu       sync.Mutex
+	data     bytes.Buffer
+	complete bool
+	overflow bool
+}
+
+func (b *sentBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.overflow {
+		if b.data.Len()+n > MaxCurlBodySize {
+			b.overflow = true
+			b.data = bytes.Buffer{}
+		} else {
+			b.data.Write(p[:n])
+		}
+	}
+	if err == io.EOF {
+		b.complete = true
+	}
+	return n, err
+}
+
+func (b *sentBody) bytes() ([]byte, bool) {
+	if b == nil {
+		return nil, false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.complete || b.overflow {
+		return nil, false
+	}
+	return b.data.Bytes(), true
+}
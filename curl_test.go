@@ -0,0 +1,99 @@
+/**
+* Created by GoLand.
+* User: luffy
+* Date: 2026-10-20
+* Time: 10:40
+ */
+package greq
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestToCurl(t *testing.T) {
+	req := NewRequest("post", "https://example.com/users/{id}")
+	req.SetPathParam("id", "7")
+	req.SetQuery("q", "a b")
+	req.SetHeader("X-Note", "it's")
+	req.AddCookie(&http.Cookie{Name: "session", Value: "abc"})
+	req.SetBodyJSON(map[string]string{"name": "luffy"})
+	req.SetProxy("http://127.0.0.1:8080")
+	req.EnableInsecureTLS(true)
+	curl, err := req.ToCurl()
+	if err != nil {
+		t.Fatalf("req.ToCurl error, err = %s", err.Error())
+	}
+	want := `curl -X POST 'https://example.com/users/7?q=a+b' -H 'Content-Type: application/json;charset=utf-8' -b session=abc ` +
+		`-H 'X-Note: it'\''s' --data-raw '{"name":"luffy"}' -x http://127.0.0.1:8080 -k`
+	if curl != want {
+		t.Errorf("curl got = %s\nwant = %s", curl, want)
+	}
+
+	path := filepath.Join(t.TempDir(), "data.bin")
+	os.WriteFile(path, []byte("data"), 0644)
+	req = NewRequest("put", "https://example.com/upload")
+	req.SetHeader("Content-Type", "application/octet-stream")
+	req.SetBodyFile(path)
+	if curl, _ := req.ToCurl(); curl != "curl -X PUT https://example.com/upload -H 'Content-Type: application/octet-stream' --data-binary @"+path {
+		t.Errorf("curl got = %s", curl)
+	}
+
+	req = NewRequest("post", "https://example.com/upload")
+	req.SetFormField("title", "a & b")
+	req.AddFile("file", "report.txt", path)
+	if curl, _ := req.ToCurl(); curl != `curl -X POST https://example.com/upload --form-string 'title=a & b' -F 'file=@`+path+`;filename="report.txt"'` {
+		t.Errorf("curl got = %s", curl)
+	}
+
+	// An inline body starting with @ must not be read from a file by curl.
+	req = NewRequest("post", "https://example.com/")
+	req.header.Del("Content-Type")
+	req.SetBody("@/etc/passwd")
+	if curl, _ := req.ToCurl(); curl != "curl -X POST https://example.com/ --data-raw @/etc/passwd" {
+		t.Errorf("curl got = %s", curl)
+	}
+
+	req = NewRequest("post", "https://example.com/upload")
+	req.AddFilePart(&FilePart{FieldName: "file", ContentType: "text/plain; charset=utf-8", Path: path})
+	if curl, _ := req.ToCurl(); curl != `curl -X POST https://example.com/upload -F 'file=@`+path+`;type="text/plain; charset=utf-8"'` {
+		t.Errorf("curl got = %s", curl)
+	}
+}
+
+func TestCurlCommand(t *testing.T) {
+	ts := server(func(w http.ResponseWriter, r *http.Request) {})
+	defer ts.Close()
+
+	client := NewClient()
+	client.SetBearerToken("token")
+	resp := client.NewRequest("get", ts.URL+"/ping").Exec()
+	if curl := resp.CurlCommand(); curl != "curl "+ts.URL+"/ping -H 'Authorization: Bearer token' -H 'Content-Type: application/x-www-form-urlencoded;charset=utf-8'" {
+		t.Errorf("curl got = %s", curl)
+	}
+
+	// The command shows what was sent, not later changes to the request.
+	ts2 := server(func(w http.ResponseWriter, r *http.Request) { ioutil.ReadAll(r.Body) })
+	defer ts2.Close()
+	req := client.NewRequest("post", ts2.URL)
+	req.SetBody(iotest.OneByteReader(strings.NewReader("it's sent")))
+	resp = req.Exec()
+	req.SetHeader("X-Later", "1")
+	req.SetBody("changed")
+	want := "curl -X POST " + ts2.URL + " -H 'Authorization: Bearer token' " +
+		"-H 'Content-Type: application/x-www-form-urlencoded;charset=utf-8' --data-raw 'it'\\''s sent'"
+	if curl := resp.CurlCommand(); curl != want {
+		t.Errorf("curl got = %s\nwant = %s", curl, want)
+	}
+
+	head := NewRequest("head", "https://example.com/")
+	head.header.Del("Content-Type")
+	if curl, _ := head.ToCurl(); curl != "curl -I https://example.com/" {
+		t.Errorf("curl got = %s", curl)
+	}
+}
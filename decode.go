@@ -0,0 +1,93 @@
+/**
+* Created by GoLand.
+* User: luffy
+* Date: 2026-10-18
+* Time: 18:30
+ */
+package greq
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+type decodeOptions struct {
+	useNumber             bool
+	disallowUnknownFields bool
+}
+
+// DecodeOption configures how a JSON response body is decoded.
+type DecodeOption func(*decodeOptions)
+
+// UseNumber controls whether numbers are decoded as json.Number instead of
+// float64 inside interface{} values. It is on by default, as in ToJSON.
+func UseNumber(enable bool) DecodeOption {
+	return func(o *decodeOptions) {
+		o.useNumber = enable
+	}
+}
+
+func DisallowUnknownFields() DecodeOption {
+	return func(o *decodeOptions) {
+		o.disallowUnknownFields = true
+	}
+}
+
+func (r *Response) decodeJSON(v interface{}, opts ...DecodeOption) error {
+	b, err := r.ToBytes()
+	if err != nil {
+		return err
+	}
+	if len(opts) == 0 {
+		codec, _ := LookupCodec(TypeJSON)
+		return codec.Unmarshal(b, v)
+	}
+	options := decodeOptions{useNumber: true}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	d := json.NewDecoder(bytes.NewReader(b))
+	if options.useNumber {
+		d.UseNumber()
+	}
+	if options.disallowUnknownFields {
+		d.DisallowUnknownFields()
+	}
+	return d.Decode(v)
+}
+
+func JSON[T any](resp *Response, opts ...DecodeOption) (T, error) {
+	var v T
+	err := resp.decodeJSON(&v, opts...)
+	return v, err
+}
+
+func XML[T any](resp *Response) (T, error) {
+	var v T
+	err := resp.ToXML(&v)
+	return v, err
+}
+
+// Decode decodes the response body with the codec registered for its
+// Content-Type, falling back to JSON. opts apply when it is decoded as JSON.
+func Decode[T any](resp *Response, opts ...DecodeOption) (T, error) {
+	if len(opts) > 0 && isJSON(resp.Header().Get("Content-Type")) {
+		return JSON[T](resp, opts...)
+	}
+	var v T
+	err := resp.Decode(&v)
+	return v, err
+}
+
+func DoJSON[T any](req *Request, opts ...DecodeOption) (T, error) {
+	return JSON[T](req.Exec(), opts...)
+}
+
+func isJSON(contentType string) bool {
+	if _, ok := LookupCodec(contentType); !ok {
+		return true
+	}
+	media := mediaType(contentType)
+	return media == "application/json" || strings.HasSuffix(media, "+json")
+}
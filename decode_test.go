@@ -0,0 +1,78 @@
+/**
+* Created by GoLand.
+* User: luffy
+* Date: 2026-10-18
+* Time: 18:55
+ */
+package greq
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+type content struct {
+	Code string `json:"code" xml:"code"`
+	Msg  string `json:"msg" xml:"msg"`
+}
+
+func TestDoJSON(t *testing.T) {
+	ts := server(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", TypeJSON)
+		w.Write([]byte(`{"code":"0","msg":"success","extra":1.5}`))
+	})
+	defer ts.Close()
+
+	c, err := DoJSON[content](NewRequest("get", ts.URL))
+	if err != nil {
+		t.Fatalf("DoJSON error, err = %s", err.Error())
+	}
+	if c != (content{Code: "0", Msg: "success"}) {
+		t.Errorf("got body = %+v", c)
+	}
+
+	resp := NewRequest("get", ts.URL).Exec()
+	if _, err := JSON[content](resp, DisallowUnknownFields()); err == nil {
+		t.Errorf("JSON with DisallowUnknownFields error got = nil, want unknown field error")
+	}
+	m, err := JSON[map[string]interface{}](resp)
+	if err != nil {
+		t.Fatalf("JSON error, err = %s", err.Error())
+	}
+	if _, ok := m["extra"].(json.Number); !ok {
+		t.Errorf("extra got = %T, want json.Number", m["extra"])
+	}
+	m, err = JSON[map[string]interface{}](resp, UseNumber(false))
+	if err != nil {
+		t.Fatalf("JSON error, err = %s", err.Error())
+	}
+	if _, ok := m["extra"].(float64); !ok {
+		t.Errorf("extra got = %T, want float64", m["extra"])
+	}
+}
+
+func TestDecodeByContentType(t *testing.T) {
+	ts := server(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("format") == "xml" {
+			w.Header().Set("Content-Type", TypeXML)
+			w.Write([]byte(`<content><code>0</code><msg>xml</msg></content>`))
+			return
+		}
+		w.Header().Set("Content-Type", TypeJSON)
+		w.Write([]byte(`{"code":"0","msg":"json"}`))
+	})
+	defer ts.Close()
+
+	for _, format := range []string{"xml", "json"} {
+		req := NewRequest("get", ts.URL)
+		req.SetParam("format", format)
+		c, err := Decode[content](req.Exec())
+		if err != nil {
+			t.Fatalf("Decode error, err = %s", err.Error())
+		}
+		if c.Msg != format {
+			t.Errorf("msg got = %s, want = %s", c.Msg, format)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+/**
+* Created by GoLand.
+* User: luffy
+* Date: 2026-10-18
+* Time: 17:20
+ */
+package greq
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+)
+
+// MaxErrorBodySize limits how much of the response body an HTTPError keeps.
+var MaxErrorBodySize = 4 << 10
+
+// HTTPError is returned for a response whose status was not expected; see
+// Request.ExpectStatus. Result holds the decoded JSON error body when an
+// error result was set.
+type HTTPError struct {
+	StatusCode int
+	Status     string
+	Header     http.Header
+	Body       []byte
+	Method     string
+	URL        string
+	Result     interface{}
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("greq: %s %s: %s", e.Method, e.URL, e.Status)
+}
+
+// statusPolicy lists the expected status codes; an empty list means any 2xx.
+type statusPolicy struct {
+	codes  []int
+	result interface{}
+}
+
+func (p *statusPolicy) expected(code int) bool {
+	if len(p.codes) == 0 {
+		return code >= 200 && code < 300
+	}
+	for _, c := range p.codes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// ExpectStatus turns any response whose status is not one of codes into an
+// *HTTPError. Without codes every 2xx status is expected.
+func (r *Request) ExpectStatus(codes ...int) {
+	if r.expect == nil {
+		r.expect = &statusPolicy{}
+	}
+	r.expect.codes = codes
+}
+
+// SetErrorResult decodes the JSON body of an unexpected response into a new
+// value of the type of v (or the type v points to), and a pointer to it is
+// then available as HTTPError.Result. v itself is not written to, so the
+// request can be executed concurrently. It implies ExpectStatus() when no
+// status policy was set.
+func (r *Request) SetErrorResult(v interface{}) {
+	if r.expect == nil {
+		r.expect = &statusPolicy{}
+	}
+	r.expect.result = v
+}
+
+// ExpectStatus sets the status policy of every request created by the client.
+func (c *Client) ExpectStatus(codes ...int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.expect == nil {
+		c.expect = &statusPolicy{}
+	}
+	c.expect.codes = codes
+}
+
+// SetErrorResult sets the type JSON error bodies are decoded into for every
+// request created by the client; see Request.SetErrorResult.
+func (c *Client) SetErrorResult(v interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.expect == nil {
+		c.expect = &statusPolicy{}
+	}
+	c.expect.result = v
+}
+
+func (p *statusPolicy) clone() *statusPolicy {
+	if p == nil {
+		return nil
+	}
+	return &statusPolicy{codes: p.codes, result: p.result}
+}
+
+// newResult returns a new value to decode an error body into, so that
+// concurrent and repeated executions do not share one.
+func (p *statusPolicy) newResult() interface{} {
+	if p.result == nil {
+		return nil
+	}
+	t := reflect.TypeOf(p.result)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return reflect.New(t).Interface()
+}
+
+func (p *statusPolicy) check(resp *Response) error {
+	if p.expected(resp.resp.StatusCode) {
+		return nil
+	}
+	body, _ := ioutil.ReadAll(io.LimitReader(resp.resp.Body, int64(MaxErrorBodySize)))
+	resp.resp.Body.Close()
+	err := newHTTPError(resp.req, resp.resp, body)
+	if result := p.newResult(); result != nil && len(body) > 0 && json.Unmarshal(body, result) == nil {
+		err.Result = result
+	}
+	return err
+}
+
+func newHTTPError(req *http.Request, resp *http.Response, body []byte) *HTTPError {
+	if len(body) > MaxErrorBodySize {
+		body = body[:MaxErrorBodySize]
+	}
+	return &HTTPError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Header:     resp.Header,
+		Body:       body,
+		Method:     req.Method,
+		URL:        req.URL.String(),
+	}
+}
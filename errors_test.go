@@ -0,0 +1,148 @@
+/**
+* Created by GoLand.
+* User: luffy
+* Date: 2026-10-18
+* Time: 17:50
+ */
+package greq
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestExpectStatus(t *testing.T) {
+	type apiError struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+	}
+	ts := server(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", TypeJSON)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"code":"E500","msg":"boom"}`))
+	})
+	defer ts.Close()
+
+	var result apiError
+	req := NewRequest("get", ts.URL+"/users")
+	req.ExpectStatus()
+	req.SetErrorResult(&result)
+	resp := req.Exec()
+	var httpErr *HTTPError
+	if !errors.As(resp.Error(), &httpErr) {
+		t.Fatalf("req.exec error got = %v, want *HTTPError", resp.Error())
+	}
+	if httpErr.StatusCode != 500 || httpErr.Method != "GET" || httpErr.URL != ts.URL+"/users" {
+		t.Errorf("http error got = %+v", httpErr)
+	}
+	if string(httpErr.Body) != `{"code":"E500","msg":"boom"}` {
+		t.Errorf("http error body got = %s", httpErr.Body)
+	}
+	if got, ok := httpErr.Result.(*apiError); !ok || got.Code != "E500" || got == &result {
+		t.Errorf("error result got = %+v", httpErr.Result)
+	}
+	if result.Code != "" {
+		t.Errorf("error result prototype was written to: %+v", result)
+	}
+}
+
+func TestClientExpectStatus(t *testing.T) {
+	type apiError struct {
+		Msg string `json:"msg"`
+	}
+	ts := server(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"msg":"not found"}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	})
+	defer ts.Close()
+
+	client := NewClient()
+	client.SetBaseURL(ts.URL)
+	client.ExpectStatus(http.StatusOK, http.StatusCreated)
+	client.SetErrorResult(&apiError{})
+	if err := client.NewRequest("post", "/users").Exec().Error(); err != nil {
+		t.Errorf("req.exec error err= %s", err.Error())
+	}
+	err := client.NewRequest("get", "/missing").Exec().Error()
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("req.exec error got = %v, want 404 *HTTPError", err)
+	}
+	if result, ok := httpErr.Result.(*apiError); !ok || result.Msg != "not found" {
+		t.Errorf("error result got = %#v", httpErr.Result)
+	}
+
+	// Values that are not pointers are decoded into a new value of their type.
+	for _, v := range []interface{}{apiError{}, map[string]interface{}{}} {
+		client.SetErrorResult(v)
+		err := client.NewRequest("get", "/missing").Exec().Error()
+		if !errors.As(err, &httpErr) {
+			t.Fatalf("req.exec error got = %v, want *HTTPError", err)
+		}
+		switch result := httpErr.Result.(type) {
+		case *apiError:
+			if result.Msg != "not found" {
+				t.Errorf("error result got = %#v", result)
+			}
+		case *map[string]interface{}:
+			if (*result)["msg"] != "not found" {
+				t.Errorf("error result got = %#v", result)
+			}
+		default:
+			t.Errorf("error result got = %#v, want a pointer to %T", httpErr.Result, v)
+		}
+	}
+}
+
+type countingBody struct {
+	io.ReadCloser
+	read   int
+	closed bool
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.read += n
+	return n, err
+}
+
+func (b *countingBody) Close() error {
+	b.closed = true
+	return b.ReadCloser.Close()
+}
+
+func TestHTTPErrorBodyLimit(t *testing.T) {
+	ts := server(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte(strings.Repeat("x", 1<<20)))
+	})
+	defer ts.Close()
+
+	var body *countingBody
+	req := NewRequest("get", ts.URL)
+	req.ExpectStatus()
+	req.Use(func(next Handler) Handler {
+		return func(req *http.Request) (*http.Response, error) {
+			resp, err := next(req)
+			if resp != nil {
+				body = &countingBody{ReadCloser: resp.Body}
+				resp.Body = body
+			}
+			return resp, err
+		}
+	})
+	var httpErr *HTTPError
+	if err := req.Exec().Error(); !errors.As(err, &httpErr) || len(httpErr.Body) != MaxErrorBodySize {
+		t.Fatalf("req.exec error got = %v", err)
+	}
+	if body.read > MaxErrorBodySize || !body.closed {
+		t.Errorf("error body read = %d bytes, closed = %v", body.read, body.closed)
+	}
+}
@@ -0,0 +1,311 @@
+/**
+* Created by GoLand.
+* User: luffy
+* Date: 2026-10-19
+* Time: 23:10
+ */
+package greq
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+type LogLevel int
+
+const (
+	LogDebug LogLevel = iota
+	LogInfo
+	LogWarn
+	LogError
+	LogOff
+)
+
+func (l LogLevel) String() string {
+	switch l {
+	case LogDebug:
+		return "debug"
+	case LogInfo:
+		return "info"
+	case LogWarn:
+		return "warn"
+	case LogError:
+		return "error"
+	}
+	return "off"
+}
+
+const redacted = "[REDACTED]"
+
+// LogEntry describes one attempt. Successful responses are logged at
+// LogInfo, 4xx at LogWarn, and 5xx and transport errors at LogError. Sizes
+// are -1 when unknown; bodies are only set when the policy logs them.
+type LogEntry struct {
+	Level          LogLevel
+	Method         string
+	URL            string
+	StatusCode     int
+	Latency        time.Duration
+	RequestHeader  http.Header
+	ResponseHeader http.Header
+	RequestSize    int64
+	ResponseSize   int64
+	RequestBody    string
+	ResponseBody   string
+	Err            error
+}
+
+type Logger interface {
+	Log(entry *LogEntry)
+}
+
+type LoggerFunc func(entry *LogEntry)
+
+func (f LoggerFunc) Log(entry *LogEntry) {
+	f(entry)
+}
+
+// LogPolicy configures logging. Entries below Level are dropped. Headers,
+// query parameters and JSON fields named in the Redact lists (matched case
+// insensitively) are replaced with [REDACTED]; bodies are logged when Body
+// is set, truncated to MaxBodySize bytes.
+//
+// An entry is logged once the response body has been read or closed, as
+// ToBytes, ToJSON and the other Response readers do, so that its size and
+// body are known. A response whose body is never read or closed, such as
+// after only calling StatusCode, is not logged; responses without a body are
+// logged right away.
+type LogPolicy struct {
+	Logger        Logger
+	Level         LogLevel
+	Body          bool
+	MaxBodySize   int
+	RedactHeaders []string
+	RedactQuery   []string
+	RedactFields  []string
+
+	once   sync.Once
+	fields *regexp.Regexp
+}
+
+// NewLogPolicy redacts the Authorization, Proxy-Authorization, Cookie and
+// Set-Cookie headers, and the usual secret query parameters and JSON fields.
+func NewLogPolicy(logger Logger, level LogLevel) *LogPolicy {
+	return &LogPolicy{
+		Logger:        logger,
+		Level:         level,
+		MaxBodySize:   1 << 10,
+		RedactHeaders: []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"},
+		RedactQuery:   []string{"access_token", "api_key", "token", "password"},
+		RedactFields:  []string{"password", "secret", "token", "access_token", "refresh_token"},
+	}
+}
+
+// SetLogger logs every attempt of the request; see LogPolicy for when
+// entries are written.
+func (r *Request) SetLogger(policy *LogPolicy) {
+	r.logging = policy
+}
+
+// SetLogger logs every attempt of the client's requests; see LogPolicy for
+// when entries are written.
+func (c *Client) SetLogger(policy *LogPolicy) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.logging = policy
+}
+
+func (p *LogPolicy) logs(level LogLevel) bool {
+	return p.Logger != nil && p.Level != LogOff && level >= p.Level
+}
+
+func (p *LogPolicy) wrap(next Handler) Handler {
+	return func(req *http.Request) (*http.Response, error) {
+		entry := &LogEntry{
+			Method:        req.Method,
+			URL:           p.redactURL(req.URL),
+			RequestHeader: p.redactHeader(req.Header),
+			RequestSize:   req.ContentLength,
+			ResponseSize:  -1,
+		}
+		if req.Body == nil || req.Body == http.NoBody {
+			entry.RequestSize = 0
+		}
+		if p.Body && req.GetBody != nil {
+			if body, err := req.GetBody(); err == nil {
+				entry.RequestBody = p.redactBody(readLimited(body, p.maxBodySize()))
+				body.Close()
+			}
+		}
+		start := time.Now()
+		resp, err := next(req)
+		entry.Latency = time.Since(start)
+		if err != nil {
+			entry.Level, entry.Err = LogError, err
+			if p.logs(entry.Level) {
+				p.Logger.Log(entry)
+			}
+			return resp, err
+		}
+		entry.StatusCode = resp.StatusCode
+		entry.ResponseHeader = p.redactHeader(resp.Header)
+		switch {
+		case resp.StatusCode >= http.StatusInternalServerError:
+			entry.Level = LogError
+		case resp.StatusCode >= http.StatusBadRequest:
+			entry.Level = LogWarn
+		default:
+			entry.Level = LogInfo
+		}
+		if !p.logs(entry.Level) {
+			return resp, nil
+		}
+		if hasNoBody(req, resp) {
+			entry.ResponseSize = 0
+			p.Logger.Log(entry)
+		} else {
+			resp.Body = &loggedBody{ReadCloser: resp.Body, policy: p, entry: entry}
+		}
+		return resp, nil
+	}
+}
+
+func (p *LogPolicy) maxBodySize() int {
+	if p.MaxBodySize <= 0 {
+		return 1 << 10
+	}
+	return p.MaxBodySize
+}
+
+func (p *LogPolicy) redactHeader(header http.Header) http.Header {
+	header = header.Clone()
+	for _, name := range p.RedactHeaders {
+		if _, ok := header[http.CanonicalHeaderKey(name)]; ok {
+			header[http.CanonicalHeaderKey(name)] = []string{redacted}
+		}
+	}
+	return header
+}
+
+func (p *LogPolicy) redactURL(u *url.URL) string {
+	if u.RawQuery == "" || len(p.RedactQuery) == 0 {
+		return u.Redacted()
+	}
+	query := u.Query()
+	for key, values := range query {
+		for _, name := range p.RedactQuery {
+			if strings.EqualFold(key, name) {
+				for i := range values {
+					values[i] = redacted
+				}
+			}
+		}
+	}
+	copied := *u
+	copied.RawQuery = strings.ReplaceAll(query.Encode(), url.QueryEscape(redacted), redacted)
+	return copied.Redacted()
+}
+
+// redactBody replaces the values of RedactFields in a JSON body. It works on
+// the text, so a body cut at MaxBodySize is still redacted.
+func (p *LogPolicy) redactBody(body []byte) string {
+	if len(p.RedactFields) == 0 {
+		return string(body)
+	}
+	p.once.Do(func() {
+		names := make([]string, len(p.RedactFields))
+		for i, name := range p.RedactFields {
+			names[i] = regexp.QuoteMeta(name)
+		}
+		p.fields = regexp.MustCompile(`(?i)("(?:` + strings.Join(names, "|") + `)"\s*:\s*)("(?:[^"\\]|\\.)*"?|[^\s,}\]]+)`)
+	})
+	return p.fields.ReplaceAllString(string(body), `${1}"`+redacted+`"`)
+}
+
+func readLimited(r io.Reader, limit int) []byte {
+	var buf bytes.Buffer
+	_, _ = io.CopyN(&buf, r, int64(limit))
+	return buf.Bytes()
+}
+
+// loggedBody keeps the start of a response body and logs the entry once the
+// body has been read or closed.
+type loggedBody struct {
+	io.ReadCloser
+	policy *LogPolicy
+	entry  *LogEntry
+	size   int64
+	body   bytes.Buffer
+	once   sync.Once
+}
+
+func (b *loggedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.size += int64(n)
+	if b.policy.Body {
+		if room := b.policy.maxBodySize() - b.body.Len(); room > 0 {
+			b.body.Write(p[:min(n, room)])
+		}
+	}
+	if err == io.EOF {
+		b.log()
+	}
+	return n, err
+}
+
+func (b *loggedBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.log()
+	return err
+}
+
+func (b *loggedBody) log() {
+	b.once.Do(func() {
+		b.entry.ResponseSize = b.size
+		if b.policy.Body {
+			b.entry.ResponseBody = b.policy.redactBody(b.body.Bytes())
+		}
+		b.policy.Logger.Log(b.entry)
+	})
+}
+
+// SlogLogger logs entries to logger, mapping LogLevel to the slog levels.
+func SlogLogger(logger *slog.Logger) Logger {
+	return LoggerFunc(func(entry *LogEntry) {
+		level := slog.LevelInfo
+		switch entry.Level {
+		case LogDebug:
+			level = slog.LevelDebug
+		case LogWarn:
+			level = slog.LevelWarn
+		case LogError:
+			level = slog.LevelError
+		}
+		attrs := []slog.Attr{
+			slog.String("method", entry.Method),
+			slog.String("url", entry.URL),
+			slog.Int("status", entry.StatusCode),
+			slog.Duration("latency", entry.Latency),
+			slog.Int64("request_size", entry.RequestSize),
+			slog.Int64("response_size", entry.ResponseSize),
+		}
+		if entry.RequestBody != "" {
+			attrs = append(attrs, slog.String("request_body", entry.RequestBody))
+		}
+		if entry.ResponseBody != "" {
+			attrs = append(attrs, slog.String("response_body", entry.ResponseBody))
+		}
+		if entry.Err != nil {
+			attrs = append(attrs, slog.String("error", entry.Err.Error()))
+		}
+		logger.LogAttrs(context.Background(), level, "greq request", attrs...)
+	})
+}
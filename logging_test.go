@@ -0,0 +1,79 @@
+/**
+* Created by GoLand.
+* User: luffy
+* Date: 2026-10-19
+* Time: 23:50
+ */
+package greq
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestLogger(t *testing.T) {
+	ts := server(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Set-Cookie", "session=secret")
+		if r.URL.Path == "/empty" {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+		w.Write([]byte(`{"id":1,"token":"abc\"def","nested":{"Password":42}}`))
+	})
+	defer ts.Close()
+
+	var entries []*LogEntry
+	policy := NewLogPolicy(LoggerFunc(func(entry *LogEntry) {
+		entries = append(entries, entry)
+	}), LogInfo)
+	policy.Body = true
+	policy.MaxBodySize = 49
+	client := NewClient()
+	client.SetBaseURL(ts.URL)
+	client.SetLogger(policy)
+	client.SetBearerToken("bearer-secret")
+
+	req := client.NewRequest("post", "/orders?api_key=k&page=2")
+	req.SetBodyJSON(map[string]string{"name": "luffy", "secret": "s3cr3t"})
+	req.Exec().ToString()
+	if len(entries) != 1 {
+		t.Fatalf("entries got = %d, want = 1", len(entries))
+	}
+	entry := entries[0]
+	if entry.Level != LogInfo || entry.StatusCode != http.StatusOK || entry.URL != ts.URL+"/orders?api_key=[REDACTED]&page=2" {
+		t.Errorf("entry got = %+v", entry)
+	}
+	if entry.RequestHeader.Get("Authorization") != redacted || entry.ResponseHeader.Get("Set-Cookie") != redacted {
+		t.Errorf("headers got = %v, %v", entry.RequestHeader, entry.ResponseHeader)
+	}
+	if entry.RequestBody != `{"name":"luffy","secret":"[REDACTED]"}` {
+		t.Errorf("request body got = %s", entry.RequestBody)
+	}
+	// The body is cut inside the nested password value.
+	if entry.ResponseBody != `{"id":1,"token":"[REDACTED]","nested":{"Password":"[REDACTED]"` || entry.ResponseSize != 52 {
+		t.Errorf("response body got = %s, size = %d", entry.ResponseBody, entry.ResponseSize)
+	}
+
+	var buf bytes.Buffer
+	policy.Logger = SlogLogger(slog.New(slog.NewTextHandler(&buf, nil)))
+	policy.Level = LogWarn
+	client.NewRequest("get", "/").Exec().ToString()
+	client.NewRequest("get", "/missing").Exec().ToString()
+	if out := buf.String(); strings.Count(out, "\n") != 1 || !strings.Contains(out, "level=WARN") || !strings.Contains(out, "status=404") {
+		t.Errorf("slog output got = %s", out)
+	}
+
+	// An empty response is logged without its body being read.
+	policy.Level = LogInfo
+	buf.Reset()
+	client.NewRequest("get", "/empty").Exec().StatusCode()
+	if out := buf.String(); !strings.Contains(out, "status=204") {
+		t.Errorf("slog output got = %s", out)
+	}
+}
@@ -0,0 +1,142 @@
+/**
+* Created by GoLand.
+* User: luffy
+* Date: 2026-10-19
+* Time: 20:20
+ */
+package greq
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MetricLabels identify the requests a metric is recorded for. Route is the
+// target template (for example /users/{id}) and StatusClass is 2xx, 3xx,
+// 4xx, 5xx or "error" for transport errors; it is empty for in-flight
+// requests.
+type MetricLabels struct {
+	Method      string
+	Host        string
+	Route       string
+	StatusClass string
+}
+
+// MetricsSink receives the metrics of every attempt sent through a Client.
+// Duration runs until the response headers arrive; ObserveRequest is called
+// once the response body has been read or closed, so size is the number of
+// body bytes read. A response with a body the caller never reads or closes
+// stays in flight; responses without a body are observed right away.
+type MetricsSink interface {
+	IncInFlight(labels MetricLabels)
+	DecInFlight(labels MetricLabels)
+	ObserveRequest(labels MetricLabels, duration time.Duration, size int64)
+}
+
+func (c *Client) SetMetrics(sink MetricsSink) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.metrics = sink
+}
+
+type routeKey struct{}
+
+// RouteTemplate returns the target of the greq Request req was built from,
+// before path parameters were filled in, without scheme, host and query.
+func RouteTemplate(req *http.Request) string {
+	route, _ := req.Context().Value(routeKey{}).(string)
+	return route
+}
+
+func routeTemplate(target string) string {
+	if i := strings.Index(target, "://"); i >= 0 {
+		target = target[i+3:]
+		if j := strings.IndexByte(target, '/'); j >= 0 {
+			target = target[j:]
+		} else {
+			target = ""
+		}
+	}
+	if i := strings.IndexByte(target, '?'); i >= 0 {
+		target = target[:i]
+	}
+	if !strings.HasPrefix(target, "/") {
+		target = "/" + target
+	}
+	return target
+}
+
+// hasNoBody reports whether resp has no body to read, so it is done as soon
+// as the headers arrive.
+func hasNoBody(req *http.Request, resp *http.Response) bool {
+	return req.Method == http.MethodHead || resp.ContentLength == 0 ||
+		resp.StatusCode == http.StatusNoContent || resp.StatusCode == http.StatusNotModified
+}
+
+func statusClass(code int) string {
+	if code < 100 || code > 599 {
+		return "error"
+	}
+	return string(rune('0'+code/100)) + "xx"
+}
+
+func measuring(sink MetricsSink, next Handler) Handler {
+	return func(req *http.Request) (*http.Response, error) {
+		labels := MetricLabels{Method: req.Method, Host: req.URL.Host, Route: RouteTemplate(req)}
+		sink.IncInFlight(labels)
+		start := time.Now()
+		resp, err := next(req)
+		took := time.Since(start)
+		if err != nil {
+			sink.DecInFlight(labels)
+			labels.StatusClass = "error"
+			sink.ObserveRequest(labels, took, 0)
+			return resp, err
+		}
+		body := &measuredBody{ReadCloser: resp.Body, sink: sink, labels: labels, class: statusClass(resp.StatusCode), took: took}
+		if hasNoBody(req, resp) {
+			body.observe()
+		} else {
+			resp.Body = body
+		}
+		return resp, nil
+	}
+}
+
+// measuredBody counts the bytes read from a response body and reports the
+// attempt at EOF or Close.
+type measuredBody struct {
+	io.ReadCloser
+	sink   MetricsSink
+	labels MetricLabels
+	class  string
+	took   time.Duration
+	size   int64
+	once   sync.Once
+}
+
+func (b *measuredBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.size += int64(n)
+	if err == io.EOF {
+		b.observe()
+	}
+	return n, err
+}
+
+func (b *measuredBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.observe()
+	return err
+}
+
+func (b *measuredBody) observe() {
+	b.once.Do(func() {
+		b.sink.DecInFlight(b.labels)
+		b.labels.StatusClass = b.class
+		b.sink.ObserveRequest(b.labels, b.took, b.size)
+	})
+}
@@ -0,0 +1,103 @@
+/**
+* Created by GoLand.
+* User: luffy
+* Date: 2026-10-19
+* Time: 21:30
+ */
+package greq
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordingSink struct {
+	mu       sync.Mutex
+	inFlight int
+	observed []MetricLabels
+	sizes    []int64
+}
+
+func (s *recordingSink) IncInFlight(MetricLabels) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.inFlight++
+}
+
+func (s *recordingSink) DecInFlight(MetricLabels) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.inFlight--
+}
+
+func (s *recordingSink) ObserveRequest(labels MetricLabels, _ time.Duration, size int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.observed = append(s.observed, labels)
+	s.sizes = append(s.sizes, size)
+}
+
+func TestRouteTemplate(t *testing.T) {
+	tests := map[string]string{
+		"":                                "/",
+		"/users/{id}?full=1":              "/users/{id}",
+		"users/{id}":                      "/users/{id}",
+		"https://api.example.com/v1/{id}": "/v1/{id}",
+		"https://api.example.com":         "/",
+	}
+	for target, want := range tests {
+		if got := routeTemplate(target); got != want {
+			t.Errorf("routeTemplate(%q) got = %s, want = %s", target, got, want)
+		}
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	ts := server(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte("down"))
+	})
+	defer ts.Close()
+
+	sink := &recordingSink{}
+	client := NewClient()
+	client.SetBaseURL(ts.URL)
+	client.SetMetrics(sink)
+	client.SetRetry(NewRetryPolicy(2))
+	req := client.NewRequest("get", "/orders/{id}")
+	req.SetPathParam("id", "7")
+	if body, _ := req.Exec().ToString(); body != "down" {
+		t.Errorf("got body = %s, want = down", body)
+	}
+	if len(sink.observed) != 2 || sink.inFlight != 0 {
+		t.Fatalf("observed = %v, in flight = %d", sink.observed, sink.inFlight)
+	}
+	if labels := sink.observed[1]; labels.Route != "/orders/{id}" || labels.StatusClass != "5xx" || labels.Method != GET {
+		t.Errorf("labels got = %+v", labels)
+	}
+	if sink.sizes[1] != 4 {
+		t.Errorf("size got = %d, want = 4", sink.sizes[1])
+	}
+}
+
+func TestMetricsWithoutBody(t *testing.T) {
+	ts := server(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	defer ts.Close()
+
+	sink := &recordingSink{}
+	client := NewClient()
+	client.SetMetrics(sink)
+	// Only the status is looked at, the body is never read or closed.
+	for _, method := range []string{GET, HEAD} {
+		if code := client.NewRequest(method, ts.URL).Exec().StatusCode(); code != http.StatusNoContent {
+			t.Fatalf("req.exec statuscode want = 204, got = %d", code)
+		}
+	}
+	if len(sink.observed) != 2 || sink.inFlight != 0 || sink.observed[1].StatusClass != "2xx" {
+		t.Errorf("observed = %v, in flight = %d", sink.observed, sink.inFlight)
+	}
+}
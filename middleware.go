@@ -0,0 +1,55 @@
+/**
+* Created by GoLand.
+* User: luffy
+* Date: 2026-10-18
+* Time: 13:20
+ */
+package greq
+
+import (
+	"net/http"
+)
+
+// Handler sends a single attempt of a request.
+type Handler func(req *http.Request) (*http.Response, error)
+
+// Middleware wraps a Handler. Middlewares run around every attempt, after the
+// *http.Request has been built; the first one registered is the outermost.
+type Middleware func(next Handler) Handler
+
+func (r *Request) Use(middlewares ...Middleware) {
+	r.middlewares = append(r.middlewares, middlewares...)
+}
+
+// Use registers middlewares for every request created by the client. They
+// run outside the middlewares registered on the request itself.
+func (c *Client) Use(middlewares ...Middleware) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.middlewares = append(c.middlewares, middlewares...)
+}
+
+// handler chains the middlewares around client. When response is not nil,
+// the phase timings of every attempt are recorded on it.
+func (r *Request) handler(client *http.Client, response *Response) Handler {
+	handler := Handler(client.Do)
+	if response != nil {
+		handler = response.timing(handler)
+	}
+	if r.signer != nil {
+		handler = signing(r.signer, handler)
+	}
+	if r.metrics != nil {
+		handler = measuring(r.metrics, handler)
+	}
+	if r.logging != nil {
+		handler = r.logging.wrap(handler)
+	}
+	if r.limits != nil {
+		handler = r.limits.wrap(handler)
+	}
+	for i := len(r.middlewares) - 1; i >= 0; i-- {
+		handler = r.middlewares[i](handler)
+	}
+	return handler
+}
@@ -0,0 +1,50 @@
+/**
+* Created by GoLand.
+* User: luffy
+* Date: 2026-10-18
+* Time: 13:40
+ */
+package greq
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestMiddlewareOrder(t *testing.T) {
+	ts := server(func(w http.ResponseWriter, r *http.Request) {
+		if v := r.Header.Get("X-Signed"); v != "client,request" {
+			t.Errorf("header X-Signed got = %s, want = client,request", v)
+		}
+	})
+	defer ts.Close()
+
+	var calls []string
+	trace := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(req *http.Request) (*http.Response, error) {
+				calls = append(calls, name+" before")
+				if v := req.Header.Get("X-Signed"); v != "" {
+					name = v + "," + name
+				}
+				req.Header.Set("X-Signed", name)
+				resp, err := next(req)
+				calls = append(calls, name+" after")
+				return resp, err
+			}
+		}
+	}
+	client := NewClient()
+	client.Use(trace("client"))
+	req := client.NewRequest("get", ts.URL)
+	req.Use(trace("request"))
+	resp := req.Exec()
+	if err := resp.Error(); err != nil {
+		t.Fatalf("req.exec error err= %s", err.Error())
+	}
+	want := []string{"client before", "request before", "client,request after", "client after"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls got = %v, want = %v", calls, want)
+	}
+}
@@ -0,0 +1,191 @@
+/**
+* Created by GoLand.
+* User: luffy
+* Date: 2026-10-18
+* Time: 14:15
+ */
+package greq
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/textproto"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+)
+
+// FilePart is a file in a multipart/form-data body. Parts backed by Path are
+// reopened for every attempt; parts backed by Reader can be sent only once.
+type FilePart struct {
+	FieldName   string
+	FileName    string
+	ContentType string
+	Header      textproto.MIMEHeader
+	Path        string
+	Reader      io.Reader
+}
+
+func (r *Request) SetFile(name, filename, path string) {
+	r.files = nil
+	r.AddFile(name, filename, path)
+}
+
+func (r *Request) AddFile(name, filename, path string) {
+	r.AddFilePart(&FilePart{FieldName: name, FileName: filename, Path: path})
+}
+
+func (r *Request) AddFileReader(name, filename, contentType string, reader io.Reader) {
+	r.AddFilePart(&FilePart{FieldName: name, FileName: filename, ContentType: contentType, Reader: reader})
+}
+
+func (r *Request) AddFilePart(part *FilePart) {
+	r.files = append(r.files, part)
+}
+
+// multipartBody streams the fields and files through an io.Pipe, so memory
+// use does not depend on the size of the files.
+type multipartBody struct {
+	boundary string
+	files    []*FilePart
+	fields   url.Values
+}
+
+func newMultipartBody(files []*FilePart, fields url.Values) (*multipartBody, error) {
+	for _, part := range files {
+		if part.Reader == nil {
+			if _, err := os.Stat(part.Path); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return &multipartBody{boundary: randomBoundary(), files: files, fields: fields}, nil
+}
+
+func (m *multipartBody) contentType() string {
+	return "multipart/form-data; boundary=" + m.boundary
+}
+
+// payload opens the body again for every attempt unless a part is backed by
+// a Reader.
+func (m *multipartBody) payload() *payload {
+	p := &payload{source: m.open, size: m.size()}
+	for _, part := range m.files {
+		if part.Reader != nil {
+			p.once = true
+		}
+	}
+	return p
+}
+
+func (m *multipartBody) open() (io.ReadCloser, error) {
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(m.write(pw, true))
+	}()
+	return pr, nil
+}
+
+// size computes the encoded length without reading file contents.
+func (m *multipartBody) size() int64 {
+	counter := &countWriter{}
+	if err := m.write(counter, false); err != nil {
+		return -1
+	}
+	size := counter.n
+	for _, part := range m.files {
+		if part.Reader != nil {
+			return -1
+		}
+		info, err := os.Stat(part.Path)
+		if err != nil {
+			return -1
+		}
+		size += info.Size()
+	}
+	return size
+}
+
+func (m *multipartBody) write(w io.Writer, content bool) error {
+	writer := multipart.NewWriter(w)
+	if err := writer.SetBoundary(m.boundary); err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(m.fields))
+	for key := range m.fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		for _, value := range m.fields[key] {
+			if err := writer.WriteField(key, value); err != nil {
+				return err
+			}
+		}
+	}
+	for _, part := range m.files {
+		partWriter, err := writer.CreatePart(part.header())
+		if err != nil {
+			return err
+		}
+		if content {
+			if err := part.copyTo(partWriter); err != nil {
+				return err
+			}
+		}
+	}
+	return writer.Close()
+}
+
+func (p *FilePart) header() textproto.MIMEHeader {
+	header := textproto.MIMEHeader{}
+	for key, values := range p.Header {
+		header[key] = append([]string(nil), values...)
+	}
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+		escapeQuotes(p.FieldName), escapeQuotes(p.FileName)))
+	if p.ContentType != "" {
+		header.Set("Content-Type", p.ContentType)
+	} else if header.Get("Content-Type") == "" {
+		header.Set("Content-Type", "application/octet-stream")
+	}
+	return header
+}
+
+func (p *FilePart) copyTo(w io.Writer) error {
+	if p.Reader != nil {
+		_, err := io.Copy(w, p.Reader)
+		return err
+	}
+	file, err := os.Open(p.Path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = io.Copy(w, file)
+	return err
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func escapeQuotes(s string) string {
+	return quoteEscaper.Replace(s)
+}
+
+func randomBoundary() string {
+	var buf [30]byte
+	_, _ = io.ReadFull(rand.Reader, buf[:])
+	return fmt.Sprintf("%x", buf[:])
+}
+
+type countWriter struct {
+	n int64
+}
+
+func (w *countWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
@@ -0,0 +1,108 @@
+/**
+* Created by GoLand.
+* User: luffy
+* Date: 2026-10-18
+* Time: 14:50
+ */
+package greq
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMultipleFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "greq")
+	if err != nil {
+		t.Fatalf("ioutil.TempDir error, err = %s", err.Error())
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "a.txt")
+	if err := ioutil.WriteFile(path, []byte("file a"), 0644); err != nil {
+		t.Fatalf("ioutil.WriteFile error, err = %s", err.Error())
+	}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(32 << 20); err != nil {
+			t.Errorf("ParseMultipartForm error, err = %s", err.Error())
+			return
+		}
+		if v := r.FormValue("tt"); v != "0326" {
+			t.Errorf("form value got = %s, want = 0326", v)
+		}
+		want := map[string]string{"a": "file a", "b": "file b"}
+		for name, content := range want {
+			file, _, err := r.FormFile(name)
+			if err != nil {
+				t.Errorf("Form file error, err = %s", err.Error())
+				return
+			}
+			data, _ := ioutil.ReadAll(file)
+			file.Close()
+			if string(data) != content {
+				t.Errorf("file %s got = %s, want = %s", name, data, content)
+			}
+		}
+		header := r.MultipartForm.File["b"][0].Header
+		if v := header.Get("Content-Type"); v != "text/plain" {
+			t.Errorf("part Content-Type got = %s, want = text/plain", v)
+		}
+		if v := header.Get("X-Part"); v != "b" {
+			t.Errorf("part X-Part got = %s, want = b", v)
+		}
+		w.Write([]byte("success"))
+	}
+	ts := server(handler)
+	defer ts.Close()
+
+	req := NewRequest("post", ts.URL)
+	req.AddFile("a", "a.txt", path)
+	req.AddFilePart(&FilePart{
+		FieldName:   "b",
+		FileName:    "b.txt",
+		ContentType: "text/plain",
+		Header:      textproto.MIMEHeader{"X-Part": {"b"}},
+		Reader:      strings.NewReader("file b"),
+	})
+	req.SetParam("tt", "0326")
+	resp := req.Exec()
+	if err := resp.Error(); err != nil {
+		t.Fatalf("req.exec error err= %s", err.Error())
+	}
+	if resp.StatusCode() != 200 {
+		t.Errorf("req.exec statuscode want = 200, got = %d", resp.StatusCode())
+	}
+}
+
+func TestFileContentLength(t *testing.T) {
+	dir, err := ioutil.TempDir("", "greq")
+	if err != nil {
+		t.Fatalf("ioutil.TempDir error, err = %s", err.Error())
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "upload.bin")
+	if err := ioutil.WriteFile(path, make([]byte, 1<<20), 0644); err != nil {
+		t.Fatalf("ioutil.WriteFile error, err = %s", err.Error())
+	}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if r.ContentLength != int64(len(body)) {
+			t.Errorf("content length got = %d, want = %d", r.ContentLength, len(body))
+		}
+	}
+	ts := server(handler)
+	defer ts.Close()
+
+	req := NewRequest("post", ts.URL)
+	req.SetFile("upload", "upload.bin", path)
+	resp := req.Exec()
+	if err := resp.Error(); err != nil {
+		t.Fatalf("req.exec error err= %s", err.Error())
+	}
+}
@@ -0,0 +1,274 @@
+/**
+* Created by GoLand.
+* User: luffy
+* Date: 2026-10-19
+* Time: 14:00
+ */
+package greq
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultTokenLeeway is how long before its expiry a cached token is
+// refreshed.
+var DefaultTokenLeeway = 30 * time.Second
+
+// Token is an OAuth2 access token.
+type Token struct {
+	AccessToken  string    `json:"access_token"`
+	TokenType    string    `json:"token_type,omitempty"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	ExpiresIn    int64     `json:"expires_in,omitempty"`
+	Expiry       time.Time `json:"-"`
+}
+
+func (t *Token) valid(leeway time.Duration) bool {
+	if t == nil || t.AccessToken == "" {
+		return false
+	}
+	return t.Expiry.IsZero() || time.Now().Add(leeway).Before(t.Expiry)
+}
+
+func (t *Token) authorization() string {
+	tokenType := t.TokenType
+	if tokenType == "" || strings.EqualFold(tokenType, "bearer") {
+		tokenType = "Bearer"
+	}
+	return tokenType + " " + t.AccessToken
+}
+
+// TokenSource returns OAuth2 tokens.
+type TokenSource interface {
+	Token(ctx context.Context) (*Token, error)
+}
+
+// ClientCredentials fetches tokens with the client credentials grant. The
+// client authenticates with HTTP Basic auth, or with client_id and
+// client_secret form fields when AuthInBody is set.
+type ClientCredentials struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+	Params       url.Values
+	AuthInBody   bool
+	// Client sends the token requests; a Client shared by all token sources
+	// is used when nil. It must not be a client that has this token source
+	// installed: the token request would wait for its own token.
+	Client *Client
+}
+
+func (c *ClientCredentials) Token(ctx context.Context) (*Token, error) {
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	if len(c.Scopes) > 0 {
+		form.Set("scope", strings.Join(c.Scopes, " "))
+	}
+	addValues(form, c.Params)
+	return fetchToken(ctx, c.Client, c.TokenURL, c.ClientID, c.ClientSecret, c.AuthInBody, form)
+}
+
+// RefreshTokenSource fetches tokens with the refresh token grant, keeping
+// the refresh token the server rotates to.
+type RefreshTokenSource struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	AuthInBody   bool
+	// Client sends the token requests, as for ClientCredentials.
+	Client *Client
+
+	mu           sync.Mutex
+	refreshToken string
+}
+
+func NewRefreshTokenSource(tokenURL, clientID, clientSecret, refreshToken string) *RefreshTokenSource {
+	return &RefreshTokenSource{
+		TokenURL:     tokenURL,
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		refreshToken: refreshToken,
+	}
+}
+
+func (s *RefreshTokenSource) Token(ctx context.Context) (*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	form := url.Values{}
+	form.Set("grant_type", "refresh_token")
+	form.Set("refresh_token", s.refreshToken)
+	token, err := fetchToken(ctx, s.Client, s.TokenURL, s.ClientID, s.ClientSecret, s.AuthInBody, form)
+	if err != nil {
+		return nil, err
+	}
+	if token.RefreshToken != "" {
+		s.refreshToken = token.RefreshToken
+	}
+	return token, nil
+}
+
+var (
+	tokenClientOnce sync.Once
+	tokenClient     *Client
+)
+
+// defaultTokenClient returns the Client sending token requests of sources
+// without their own, so fetches reuse connections.
+func defaultTokenClient() *Client {
+	tokenClientOnce.Do(func() {
+		tokenClient = NewClient()
+	})
+	return tokenClient
+}
+
+func fetchToken(ctx context.Context, client *Client, tokenURL, clientID, clientSecret string, authInBody bool, form url.Values) (*Token, error) {
+	if client == nil {
+		client = defaultTokenClient()
+	}
+	req := client.NewRequest(POST, tokenURL)
+	req.SetContext(ctx)
+	req.SetHeader("Accept", "application/json")
+	if authInBody {
+		form.Set("client_id", clientID)
+		form.Set("client_secret", clientSecret)
+	} else {
+		req.SetBasicAuth(url.QueryEscape(clientID), url.QueryEscape(clientSecret))
+	}
+	req.SetFormValues(form)
+	req.ExpectStatus()
+	var token Token
+	if err := req.Exec().ToJSON(&token); err != nil {
+		return nil, err
+	}
+	if token.AccessToken == "" {
+		return nil, errors.New("greq: token response has no access_token")
+	}
+	if token.ExpiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	}
+	return &token, nil
+}
+
+// CachedTokenSource caches the token of another source until shortly before
+// it expires. Concurrent callers share a single in-flight fetch.
+type CachedTokenSource struct {
+	src    TokenSource
+	leeway time.Duration
+
+	mu    sync.Mutex
+	token *Token
+	call  *tokenCall
+}
+
+type tokenCall struct {
+	done  chan struct{}
+	token *Token
+	err   error
+}
+
+func NewCachedTokenSource(src TokenSource, leeway time.Duration) *CachedTokenSource {
+	return &CachedTokenSource{src: src, leeway: leeway}
+}
+
+func (s *CachedTokenSource) Token(ctx context.Context) (*Token, error) {
+	s.mu.Lock()
+	if s.token.valid(s.leeway) {
+		token := s.token
+		s.mu.Unlock()
+		return token, nil
+	}
+	call := s.call
+	if call == nil {
+		call = &tokenCall{done: make(chan struct{})}
+		s.call = call
+		// The fetch outlives the caller that started it, so cancelling one
+		// request does not fail the others waiting for the same token.
+		go s.fetch(context.WithoutCancel(ctx), call)
+	}
+	s.mu.Unlock()
+	select {
+	case <-call.done:
+		return call.token, call.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (s *CachedTokenSource) fetch(ctx context.Context, call *tokenCall) {
+	call.token, call.err = s.src.Token(ctx)
+	s.mu.Lock()
+	if call.err == nil {
+		s.token = call.token
+	}
+	s.call = nil
+	s.mu.Unlock()
+	close(call.done)
+}
+
+// Invalidate drops token from the cache if it is still the cached one, so the
+// next call fetches a new token.
+func (s *CachedTokenSource) Invalidate(token *Token) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token == token {
+		s.token = nil
+	}
+}
+
+// OAuth2 returns a middleware that authorizes requests with tokens from src.
+// When the server answers 401 the token is invalidated, and the request is
+// sent once more with a fresh token.
+func OAuth2(src TokenSource) Middleware {
+	cached, ok := src.(*CachedTokenSource)
+	if !ok {
+		cached = NewCachedTokenSource(src, DefaultTokenLeeway)
+	}
+	return func(next Handler) Handler {
+		return func(req *http.Request) (*http.Response, error) {
+			token, err := cached.Token(req.Context())
+			if err != nil {
+				return nil, err
+			}
+			req.Header.Set("Authorization", token.authorization())
+			resp, err := next(req)
+			if err != nil || resp.StatusCode != http.StatusUnauthorized {
+				return resp, err
+			}
+			if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+				return resp, err
+			}
+			cached.Invalidate(token)
+			token, err = cached.Token(req.Context())
+			if err != nil {
+				return resp, nil
+			}
+			retry := req.Clone(req.Context())
+			if req.GetBody != nil {
+				if retry.Body, err = req.GetBody(); err != nil {
+					return resp, nil
+				}
+			}
+			_, _ = io.CopyN(ioutil.Discard, resp.Body, 4<<10)
+			resp.Body.Close()
+			retry.Header.Set("Authorization", token.authorization())
+			return next(retry)
+		}
+	}
+}
+
+func (r *Request) SetTokenSource(src TokenSource) {
+	r.Use(OAuth2(src))
+}
+
+func (c *Client) SetTokenSource(src TokenSource) {
+	c.Use(OAuth2(src))
+}
@@ -0,0 +1,108 @@
+/**
+* Created by GoLand.
+* User: luffy
+* Date: 2026-10-19
+* Time: 14:50
+ */
+package greq
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func tokenServer(t *testing.T, issued *int32) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("r.ParseForm error, got = %s", err.Error())
+		}
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "id" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+		n := atomic.AddInt32(issued, 1)
+		w.Header().Set("Content-Type", TypeJSON)
+		fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"bearer","expires_in":3600,"refresh_token":"refresh-%d","grant":"%s"}`,
+			n, n, r.PostForm.Get("grant_type"))
+	}
+}
+
+func TestOAuth2ClientCredentials(t *testing.T) {
+	var issued int32
+	tokens := server(tokenServer(t, &issued))
+	defer tokens.Close()
+	api := server(func(w http.ResponseWriter, r *http.Request) {
+		// token-1 is revoked on the server side.
+		if r.Header.Get("Authorization") != "Bearer token-2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte("ok"))
+	})
+	defer api.Close()
+
+	client := NewClient()
+	client.SetBaseURL(api.URL)
+	client.SetTokenSource(&ClientCredentials{TokenURL: tokens.URL, ClientID: "id", ClientSecret: "secret", Scopes: []string{"read"}})
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req := client.NewRequest("post", "/orders")
+			req.SetBody("payload")
+			resp := req.Exec()
+			if resp.StatusCode() != http.StatusOK {
+				t.Errorf("req.exec statuscode want = 200, got = %d", resp.StatusCode())
+			}
+		}()
+	}
+	wg.Wait()
+	if n := atomic.LoadInt32(&issued); n != 2 {
+		t.Errorf("tokens issued got = %d, want = 2", n)
+	}
+}
+
+func TestRefreshTokenSource(t *testing.T) {
+	var issued, conns int32
+	tokens := httptest.NewUnstartedServer(tokenServer(t, &issued))
+	tokens.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&conns, 1)
+		}
+	}
+	tokens.Start()
+	defer tokens.Close()
+
+	src := NewRefreshTokenSource(tokens.URL, "id", "secret", "refresh-0")
+	cached := NewCachedTokenSource(src, time.Hour+time.Minute)
+	for i := 1; i <= 2; i++ {
+		token, err := cached.Token(context.Background())
+		if err != nil {
+			t.Fatalf("cached.Token error, err = %s", err.Error())
+		}
+		if want := fmt.Sprintf("token-%d", i); token.AccessToken != want {
+			t.Errorf("access token got = %s, want = %s", token.AccessToken, want)
+		}
+	}
+	if src.refreshToken != "refresh-2" {
+		t.Errorf("refresh token got = %s, want = refresh-2", src.refreshToken)
+	}
+	if n := atomic.LoadInt32(&conns); n != 1 {
+		t.Errorf("token connections got = %d, want = 1", n)
+	}
+
+	src = NewRefreshTokenSource(tokens.URL, "id", "wrong", "refresh-0")
+	if _, err := src.Token(context.Background()); err == nil {
+		t.Errorf("src.Token error got = nil, want *HTTPError")
+	}
+}
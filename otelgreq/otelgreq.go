@@ -0,0 +1,105 @@
+/**
+* Created by GoLand.
+* User: luffy
+* Date: 2026-10-19
+* Time: 19:20
+ */
+
+// Package otelgreq traces greq requests with OpenTelemetry.
+package otelgreq
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/varluffy/greq"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/varluffy/greq/otelgreq"
+
+type config struct {
+	provider    trace.TracerProvider
+	propagators propagation.TextMapPropagator
+	spanName    func(req *http.Request) string
+}
+
+type Option func(*config)
+
+// WithTracerProvider sets the provider of the tracer; the global one by default.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.provider = provider
+	}
+}
+
+// WithPropagators sets the propagators injecting the trace context into
+// request headers; the global ones by default.
+func WithPropagators(propagators propagation.TextMapPropagator) Option {
+	return func(c *config) {
+		c.propagators = propagators
+	}
+}
+
+// WithSpanNameFormatter names spans; "HTTP <method>" by default.
+func WithSpanNameFormatter(fn func(req *http.Request) string) Option {
+	return func(c *config) {
+		c.spanName = fn
+	}
+}
+
+// Middleware starts a client span for every attempt, as a child of the
+// request context (see greq.Request.SetContext), and injects the span into
+// the traceparent and tracestate headers.
+func Middleware(opts ...Option) greq.Middleware {
+	cfg := &config{
+		provider:    otel.GetTracerProvider(),
+		propagators: otel.GetTextMapPropagator(),
+		spanName: func(req *http.Request) string {
+			return "HTTP " + req.Method
+		},
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	tracer := cfg.provider.Tracer(tracerName)
+	return func(next greq.Handler) greq.Handler {
+		return func(req *http.Request) (*http.Response, error) {
+			ctx, span := tracer.Start(req.Context(), cfg.spanName(req),
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(requestAttributes(req)...))
+			defer span.End()
+			req = req.WithContext(ctx)
+			cfg.propagators.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+			resp, err := next(req)
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				return resp, err
+			}
+			span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+			if resp.StatusCode >= http.StatusBadRequest {
+				span.SetAttributes(attribute.String("error.type", strconv.Itoa(resp.StatusCode)))
+				span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+			}
+			return resp, nil
+		}
+	}
+}
+
+func requestAttributes(req *http.Request) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		attribute.String("http.request.method", req.Method),
+		attribute.String("url.full", req.URL.Redacted()),
+		attribute.String("server.address", req.URL.Hostname()),
+	}
+	if port, err := strconv.Atoi(req.URL.Port()); err == nil {
+		attrs = append(attrs, attribute.Int("server.port", port))
+	}
+	return attrs
+}
@@ -0,0 +1,79 @@
+/**
+* Created by GoLand.
+* User: luffy
+* Date: 2026-10-19
+* Time: 19:50
+ */
+package otelgreq
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/varluffy/greq"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestMiddleware(t *testing.T) {
+	var hits int32
+	var traceparents []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparents = append(traceparents, r.Header.Get("traceparent"))
+		if atomic.AddInt32(&hits, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer ts.Close()
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "parent")
+
+	client := greq.NewClient()
+	client.Use(Middleware(WithTracerProvider(provider), WithPropagators(propagation.TraceContext{})))
+	client.SetRetry(greq.NewRetryPolicy(2))
+	req := client.NewRequest("get", ts.URL)
+	req.SetContext(ctx)
+	if resp := req.Exec(); resp.StatusCode() != http.StatusOK {
+		t.Fatalf("req.exec statuscode want = 200, got = %d", resp.StatusCode())
+	}
+	parent.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 3 {
+		t.Fatalf("spans got = %d, want = 3", len(spans))
+	}
+	for i, span := range spans[:2] {
+		if span.SpanKind != trace.SpanKindClient || span.Parent.SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("span %d is not a client span of the parent", i)
+		}
+		want := "00-" + span.SpanContext.TraceID().String() + "-" + span.SpanContext.SpanID().String() + "-01"
+		if traceparents[i] != want {
+			t.Errorf("traceparent got = %s, want = %s", traceparents[i], want)
+		}
+	}
+	if spans[0].Status.Code != codes.Error || !hasAttribute(spans[0].Attributes, attribute.Int("http.response.status_code", 503)) {
+		t.Errorf("first attempt got status = %v, attributes = %v", spans[0].Status, spans[0].Attributes)
+	}
+	if !hasAttribute(spans[1].Attributes, attribute.String("http.request.method", "GET")) ||
+		!hasAttribute(spans[1].Attributes, attribute.String("url.full", ts.URL)) {
+		t.Errorf("second attempt got attributes = %v", spans[1].Attributes)
+	}
+}
+
+func hasAttribute(attrs []attribute.KeyValue, want attribute.KeyValue) bool {
+	for _, attr := range attrs {
+		if attr == want {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+/**
+* Created by GoLand.
+* User: luffy
+* Date: 2026-10-19
+* Time: 20:50
+ */
+
+// Package promgreq records greq client metrics with Prometheus.
+package promgreq
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/varluffy/greq"
+)
+
+// Sink is a greq.MetricsSink and a prometheus.Collector. Register it with a
+// registry and pass it to greq.Client.SetMetrics.
+type Sink struct {
+	inFlight *prometheus.GaugeVec
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	size     *prometheus.SummaryVec
+}
+
+// New creates the metrics under namespace, with the given latency buckets
+// (prometheus.DefBuckets when empty).
+func New(namespace string, buckets ...float64) *Sink {
+	if len(buckets) == 0 {
+		buckets = prometheus.DefBuckets
+	}
+	labels := []string{"method", "host", "route", "status_class"}
+	return &Sink{
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "http_client",
+			Name:      "requests_in_flight",
+			Help:      "Number of requests being sent or whose response body is being read.",
+		}, labels[:3]),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http_client",
+			Name:      "requests_total",
+			Help:      "Number of requests sent, counting every attempt.",
+		}, labels),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http_client",
+			Name:      "request_duration_seconds",
+			Help:      "Time until the response headers arrived.",
+			Buckets:   buckets,
+		}, labels),
+		size: prometheus.NewSummaryVec(prometheus.SummaryOpts{
+			Namespace:  namespace,
+			Subsystem:  "http_client",
+			Name:       "response_size_bytes",
+			Help:       "Size of the response bodies read.",
+			Objectives: map[float64]float64{0.5: 0.05, 0.9: 0.01, 0.99: 0.001},
+		}, labels),
+	}
+}
+
+func (s *Sink) IncInFlight(labels greq.MetricLabels) {
+	s.inFlight.WithLabelValues(labels.Method, labels.Host, labels.Route).Inc()
+}
+
+func (s *Sink) DecInFlight(labels greq.MetricLabels) {
+	s.inFlight.WithLabelValues(labels.Method, labels.Host, labels.Route).Dec()
+}
+
+func (s *Sink) ObserveRequest(labels greq.MetricLabels, duration time.Duration, size int64) {
+	values := []string{labels.Method, labels.Host, labels.Route, labels.StatusClass}
+	s.requests.WithLabelValues(values...).Inc()
+	s.duration.WithLabelValues(values...).Observe(duration.Seconds())
+	s.size.WithLabelValues(values...).Observe(float64(size))
+}
+
+func (s *Sink) Describe(ch chan<- *prometheus.Desc) {
+	s.inFlight.Describe(ch)
+	s.requests.Describe(ch)
+	s.duration.Describe(ch)
+	s.size.Describe(ch)
+}
+
+func (s *Sink) Collect(ch chan<- prometheus.Metric) {
+	s.inFlight.Collect(ch)
+	s.requests.Collect(ch)
+	s.duration.Collect(ch)
+	s.size.Collect(ch)
+}
@@ -0,0 +1,56 @@
+/**
+* Created by GoLand.
+* User: luffy
+* Date: 2026-10-19
+* Time: 21:10
+ */
+package promgreq
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/varluffy/greq"
+)
+
+func TestSink(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/users/2" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+		w.Write([]byte("hello"))
+	}))
+	defer ts.Close()
+	host, _ := url.Parse(ts.URL)
+
+	sink := New("test")
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(sink)
+	client := greq.NewClient()
+	client.SetBaseURL(ts.URL)
+	client.SetMetrics(sink)
+	for i := 1; i <= 3; i++ {
+		req := client.NewRequest("get", "/users/{id}")
+		req.SetPathParam("id", string(rune('0'+i)))
+		req.Exec().ToString()
+	}
+
+	requests := sink.requests.WithLabelValues("GET", host.Host, "/users/{id}", "2xx")
+	if n := testutil.ToFloat64(requests); n != 2 {
+		t.Errorf("2xx requests got = %v, want = 2", n)
+	}
+	requests = sink.requests.WithLabelValues("GET", host.Host, "/users/{id}", "4xx")
+	if n := testutil.ToFloat64(requests); n != 1 {
+		t.Errorf("4xx requests got = %v, want = 1", n)
+	}
+	if n := testutil.ToFloat64(sink.inFlight.WithLabelValues("GET", host.Host, "/users/{id}")); n != 0 {
+		t.Errorf("in flight got = %v, want = 0", n)
+	}
+	if n, err := testutil.GatherAndCount(registry, "test_http_client_response_size_bytes"); err != nil || n != 2 {
+		t.Errorf("response size series got = %d, err = %v", n, err)
+	}
+}
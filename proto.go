@@ -0,0 +1,55 @@
+/**
+* Created by GoLand.
+* User: luffy
+* Date: 2026-10-18
+* Time: 20:40
+ */
+package greq
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+)
+
+const TypeProtobuf = "application/x-protobuf"
+
+func init() {
+	RegisterCodec(TypeProtobuf, protoCodec{})
+	RegisterCodec("application/protobuf", protoCodec{})
+}
+
+type protoCodec struct{}
+
+func (protoCodec) Marshal(v interface{}) ([]byte, error) {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("greq: %T is not a proto.Message", v)
+	}
+	return proto.Marshal(m)
+}
+
+func (protoCodec) Unmarshal(data []byte, v interface{}) error {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("greq: %T is not a proto.Message", v)
+	}
+	return proto.Unmarshal(data, m)
+}
+
+// SetBodyProto encodes m as the request body and, unless the request already
+// has an Accept header, asks for a protobuf response too.
+func (r *Request) SetBodyProto(m proto.Message) {
+	r.encodeBody(TypeProtobuf, m)
+	if r.header.Get("Accept") == "" {
+		r.SetHeader("Accept", TypeProtobuf)
+	}
+}
+
+func (r *Response) ToProto(m proto.Message) error {
+	b, err := r.ToBytes()
+	if err != nil {
+		return err
+	}
+	return proto.Unmarshal(b, m)
+}
@@ -0,0 +1,51 @@
+/**
+* Created by GoLand.
+* User: luffy
+* Date: 2026-10-18
+* Time: 21:00
+ */
+package greq
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestProtoBody(t *testing.T) {
+	ts := server(func(w http.ResponseWriter, r *http.Request) {
+		if v := r.Header.Get("Content-Type"); v != TypeProtobuf {
+			t.Errorf("header Content-Type got = %s, want = %s", v, TypeProtobuf)
+		}
+		if v := r.Header.Get("Accept"); v != TypeProtobuf {
+			t.Errorf("header Accept got = %s, want = %s", v, TypeProtobuf)
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		var in wrapperspb.StringValue
+		if err := proto.Unmarshal(body, &in); err != nil {
+			t.Errorf("proto.Unmarshal error, err = %s", err.Error())
+		}
+		out, _ := proto.Marshal(wrapperspb.String("hello " + in.GetValue()))
+		w.Header().Set("Content-Type", TypeProtobuf)
+		w.Write(out)
+	})
+	defer ts.Close()
+
+	req := NewRequest("post", ts.URL)
+	req.SetBodyProto(wrapperspb.String("luffy"))
+	resp := req.Exec()
+	var out wrapperspb.StringValue
+	if err := resp.ToProto(&out); err != nil {
+		t.Fatalf("resp.ToProto error, err = %s", err.Error())
+	}
+	if out.GetValue() != "hello luffy" {
+		t.Errorf("got value = %s, want = hello luffy", out.GetValue())
+	}
+	out.Reset()
+	if err := resp.Decode(&out); err != nil || out.GetValue() != "hello luffy" {
+		t.Errorf("resp.Decode got = %s, err = %v", out.GetValue(), err)
+	}
+}
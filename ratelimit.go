@@ -0,0 +1,195 @@
+/**
+* Created by GoLand.
+* User: luffy
+* Date: 2026-10-19
+* Time: 17:10
+ */
+package greq
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token bucket holding up to burst tokens, refilled at rate
+// tokens per second. A rate of zero or less does not limit.
+type RateLimiter struct {
+	rate  float64
+	burst float64
+
+	mu       sync.Mutex
+	tokens   float64
+	last     time.Time
+	blocked  time.Time
+	adaptive bool
+}
+
+func NewRateLimiter(rate float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{rate: rate, burst: float64(burst), tokens: float64(burst)}
+}
+
+// SetAdaptive makes the limiter follow X-RateLimit-Remaining and
+// X-RateLimit-Reset response headers: once the server reports no remaining
+// requests, the limiter waits until the reset time.
+func (l *RateLimiter) SetAdaptive(adaptive bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.adaptive = adaptive
+}
+
+// Wait blocks until a request may be sent or ctx is done.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	var wait time.Duration
+	if l.rate > 0 {
+		l.refill(now)
+		l.tokens--
+		if l.tokens < 0 {
+			wait = time.Duration(-l.tokens / l.rate * float64(time.Second))
+		}
+	}
+	if blocked := l.blocked.Sub(now); blocked > wait {
+		wait = blocked
+	}
+	l.mu.Unlock()
+	if wait <= 0 {
+		return nil
+	}
+	if err := sleep(ctx, wait); err != nil {
+		if l.rate > 0 {
+			l.mu.Lock()
+			l.tokens++
+			l.mu.Unlock()
+		}
+		return err
+	}
+	return nil
+}
+
+func (l *RateLimiter) refill(now time.Time) {
+	if !l.last.IsZero() {
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+	l.last = now
+}
+
+func (l *RateLimiter) observe(resp *http.Response) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.adaptive {
+		return
+	}
+	remaining, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	if err != nil || remaining > 0 {
+		return
+	}
+	reset, ok := rateLimitReset(resp.Header.Get("X-RateLimit-Reset"))
+	if !ok {
+		return
+	}
+	if reset.After(l.blocked) {
+		l.blocked = reset
+	}
+	if l.rate > 0 {
+		l.refill(time.Now())
+		l.tokens = 0
+	}
+}
+
+// rateLimitReset parses X-RateLimit-Reset, which servers send either as
+// seconds from now or as a Unix timestamp.
+func rateLimitReset(value string) (time.Time, bool) {
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || seconds < 0 {
+		return time.Time{}, false
+	}
+	if seconds > 1e9 {
+		return time.Unix(seconds, 0), true
+	}
+	return time.Now().Add(time.Duration(seconds) * time.Second), true
+}
+
+// rateLimits holds the limiters of a Client: one for every request and one
+// per host.
+type rateLimits struct {
+	mu     sync.RWMutex
+	global *RateLimiter
+	hosts  map[string]*RateLimiter
+}
+
+func (l *rateLimits) limiters(req *http.Request) []*RateLimiter {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	var limiters []*RateLimiter
+	if l.global != nil {
+		limiters = append(limiters, l.global)
+	}
+	host, ok := l.hosts[req.URL.Host]
+	if !ok {
+		host = l.hosts[req.URL.Hostname()]
+	}
+	if host != nil {
+		limiters = append(limiters, host)
+	}
+	return limiters
+}
+
+func (l *rateLimits) wrap(next Handler) Handler {
+	return func(req *http.Request) (*http.Response, error) {
+		limiters := l.limiters(req)
+		for _, limiter := range limiters {
+			if err := limiter.Wait(req.Context()); err != nil {
+				return nil, err
+			}
+		}
+		resp, err := next(req)
+		if resp != nil {
+			for _, limiter := range limiters {
+				limiter.observe(resp)
+			}
+		}
+		return resp, err
+	}
+}
+
+// SetRateLimiter limits every request sent through the client. Requests wait
+// for the limiter right before each attempt is sent.
+func (c *Client) SetRateLimiter(limiter *RateLimiter) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	limits := c.rateLimits()
+	limits.mu.Lock()
+	limits.global = limiter
+	limits.mu.Unlock()
+}
+
+// SetHostRateLimiter limits the requests sent to host, given either as
+// host:port or as a bare host name. It applies on top of SetRateLimiter.
+func (c *Client) SetHostRateLimiter(host string, limiter *RateLimiter) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	limits := c.rateLimits()
+	limits.mu.Lock()
+	if limits.hosts == nil {
+		limits.hosts = map[string]*RateLimiter{}
+	}
+	limits.hosts[host] = limiter
+	limits.mu.Unlock()
+}
+
+func (c *Client) rateLimits() *rateLimits {
+	if c.limits == nil {
+		c.limits = &rateLimits{}
+	}
+	return c.limits
+}
@@ -0,0 +1,66 @@
+/**
+* Created by GoLand.
+* User: luffy
+* Date: 2026-10-19
+* Time: 17:50
+ */
+package greq
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	ts := server(func(w http.ResponseWriter, r *http.Request) {})
+	defer ts.Close()
+	host, _ := url.Parse(ts.URL)
+
+	client := NewClient()
+	client.SetBaseURL(ts.URL)
+	client.SetHostRateLimiter("example.com", NewRateLimiter(0.1, 1))
+	client.SetHostRateLimiter(host.Host, NewRateLimiter(20, 2))
+	start := time.Now()
+	for i := 0; i < 6; i++ {
+		if err := client.NewRequest("get", "/").Exec().Error(); err != nil {
+			t.Fatalf("req.exec error, err = %s", err.Error())
+		}
+	}
+	// Two requests fit the burst, the other four wait 50ms each.
+	if took := time.Since(start); took < 190*time.Millisecond || took > time.Second {
+		t.Errorf("took = %s, want about 200ms", took)
+	}
+
+	client.SetRateLimiter(NewRateLimiter(1, 1))
+	client.NewRequest("get", "/").Exec()
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	req := client.NewRequest("get", "/")
+	req.SetContext(ctx)
+	if err := req.Exec().Error(); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("req.exec error got = %v, want = context.DeadlineExceeded", err)
+	}
+}
+
+func TestRateLimiterAdaptive(t *testing.T) {
+	ts := server(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", "1")
+	})
+	defer ts.Close()
+
+	limiter := NewRateLimiter(100, 10)
+	limiter.SetAdaptive(true)
+	client := NewClient()
+	client.SetRateLimiter(limiter)
+	client.NewRequest("get", ts.URL).Exec()
+	start := time.Now()
+	client.NewRequest("get", ts.URL).Exec()
+	if took := time.Since(start); took < 900*time.Millisecond {
+		t.Errorf("took = %s, want about 1s", took)
+	}
+}
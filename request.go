@@ -7,18 +7,14 @@
 package greq
 
 import (
-	"bytes"
 	"context"
 	"crypto/tls"
-	"encoding/json"
-	"encoding/xml"
+	"errors"
+	"fmt"
 	"io"
-	"mime/multipart"
-	"net"
 	"net/http"
-	"net/http/cookiejar"
+	"net/textproto"
 	"net/url"
-	"os"
 	"strings"
 	"time"
 )
@@ -46,34 +42,44 @@ const (
 )
 
 type Request struct {
+	baseURL string
 	target  string
 	method  string
 	header  http.Header
+	query   url.Values
+	form    url.Values
 	params  url.Values
-	body    io.Reader
+	body    *payload
 	client  *http.Client
 	cookies []*http.Cookie
 	proxy   string
 	ctx     context.Context
-	file    *file
+	files   []*FilePart
+	retry   *RetryPolicy
+	expect  *statusPolicy
 	err     error
-	req     *http.Request
-}
 
-type file struct {
-	name     string
-	path     string
-	filename string
+	pathParams  map[string]string
+	middlewares []Middleware
+	signer      Signer
+	limits      *rateLimits
+	metrics     MetricsSink
+	logging     *LogPolicy
 }
 
 func NewRequest(method, target string) *Request {
+	req := newRequest(method, target)
+	req.SetDefaultClient()
+	return req
+}
+
+func newRequest(method, target string) *Request {
 	req := &Request{}
 	req.header = http.Header{}
 	req.SetContentType(TypeUrlencoded)
 	req.target = target
 	req.method = strings.ToUpper(method)
 	req.params = url.Values{}
-	req.SetDefaultClient()
 	return req
 }
 
@@ -93,10 +99,66 @@ func (r *Request) SetHttpHeader(header http.Header) {
 	r.header = header
 }
 
-func (r *Request) SetBody(body io.Reader) {
-	r.body = body
+// SetBody sets the request body. Readers, byte slices, strings and body
+// functions (see SetBodyFunc) are sent as they are; any other value is
+// encoded with the codec registered for the request's Content-Type, or as
+// JSON when there is none.
+func (r *Request) SetBody(body interface{}) {
+	switch v := body.(type) {
+	case nil:
+		r.body = nil
+	case func() (io.ReadCloser, error):
+		r.SetBodyFunc(v, -1)
+	case io.Reader:
+		r.body = newPayload(v)
+	case []byte:
+		r.body = &payload{data: v}
+	case string:
+		r.body = &payload{data: []byte(v)}
+	default:
+		r.encodeBody(r.header.Get("Content-Type"), v)
+	}
+}
+
+func (r *Request) AddQuery(key, value string) {
+	if r.query == nil {
+		r.query = url.Values{}
+	}
+	r.query.Add(key, value)
+}
+
+func (r *Request) SetQuery(key, value string) {
+	if r.query == nil {
+		r.query = url.Values{}
+	}
+	r.query.Set(key, value)
+}
+
+func (r *Request) SetQueryValues(query url.Values) {
+	r.query = query
+}
+
+func (r *Request) AddFormField(key, value string) {
+	if r.form == nil {
+		r.form = url.Values{}
+	}
+	r.form.Add(key, value)
+}
+
+func (r *Request) SetFormField(key, value string) {
+	if r.form == nil {
+		r.form = url.Values{}
+	}
+	r.form.Set(key, value)
 }
 
+func (r *Request) SetFormValues(form url.Values) {
+	r.form = form
+}
+
+// AddParam, SetParam and SetParams predate the query and form APIs. Params go
+// to the query string for GET and HEAD requests and when a body is set, and
+// are sent as form fields otherwise.
 func (r *Request) AddParam(key, value string) {
 	r.params.Add(key, value)
 }
@@ -111,18 +173,26 @@ func (r *Request) SetParams(params url.Values) {
 
 func (r *Request) SetProxy(proxyURL string) {
 	r.proxy = proxyURL
+	r.applyProxy()
 }
 
-func (r *Request) SetFile(name, filename, path string) {
-	r.file = &file{
-		name:     name,
-		filename: filename,
-		path:     path,
+func (r *Request) applyProxy() {
+	if r.proxy == "" {
+		return
+	}
+	u, err := url.Parse(r.proxy)
+	if err != nil {
+		r.err = err
+		return
+	}
+	if transport := r.getTransport(); transport != nil {
+		transport.Proxy = http.ProxyURL(u)
 	}
 }
 
 func (r *Request) SetClient(client *http.Client) {
 	r.client = client
+	r.applyProxy()
 }
 
 func (r *Request) GetClient() *http.Client {
@@ -133,28 +203,28 @@ func (r *Request) GetClient() *http.Client {
 }
 
 func (r *Request) SetDefaultClient() {
-	jar, _ := cookiejar.New(nil)
-	transport := &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: false},
-		DialContext: (&net.Dialer{
-			Timeout:   30 * time.Second,
-			KeepAlive: 30 * time.Second,
-			DualStack: true,
-		}).DialContext,
-		MaxIdleConns:          100,
-		IdleConnTimeout:       90 * time.Second,
-		TLSHandshakeTimeout:   10 * time.Second,
-		ExpectContinueTimeout: 1 * time.Second,
-	}
-	r.client = &http.Client{
-		Jar:       jar,
-		Transport: transport,
-		Timeout:   30 * time.Second,
-	}
+	r.client = newHttpClient()
+	r.applyProxy()
 }
 
+// ownClient copies the client before every change. The client may be shared
+// with a Client or with clones of the request, so per-request settings such
+// as timeouts must not be made in place.
+func (r *Request) ownClient() *http.Client {
+	c := *r.GetClient()
+	r.client = &c
+	return r.client
+}
+
+// getTransport returns a copy of the transport the request may modify, which
+// gives up connection reuse for this request only.
 func (r *Request) getTransport() *http.Transport {
-	transport, _ := r.GetClient().Transport.(*http.Transport)
+	client := r.ownClient()
+	transport, _ := client.Transport.(*http.Transport)
+	if transport != nil {
+		transport = transport.Clone()
+		client.Transport = transport
+	}
 	return transport
 }
 
@@ -170,7 +240,7 @@ func (r *Request) EnableInsecureTLS(enable bool) {
 }
 
 func (r *Request) SetTimeout(d time.Duration) {
-	r.GetClient().Timeout = d
+	r.ownClient().Timeout = d
 }
 
 func (r *Request) AddCookie(cookie *http.Cookie) {
@@ -182,117 +252,175 @@ func (r *Request) SetCookies(cookies []*http.Cookie) {
 }
 
 func (r *Request) SetBodyJSON(v interface{}) {
-	data, err := json.Marshal(v)
-	if err != nil {
-		r.err = err
-	}
-	r.SetBody(bytes.NewBuffer(data))
-	r.SetContentType(TypeJSON)
+	r.encodeBody(TypeJSON, v)
 }
 
 func (r *Request) SetBodyXML(v interface{}) {
-	data, err := xml.Marshal(v)
-	if err != nil {
-		r.err = err
-	}
-	r.SetBody(bytes.NewBuffer(data))
-	r.SetContentType(TypeXML)
+	r.encodeBody(TypeXML, v)
 }
+
 func (r *Request) SetContext(ctx context.Context) {
 	r.ctx = ctx
 }
 
+// Clone returns a deep copy of the request that can be changed and executed
+// independently. A body read from a one-shot reader is buffered in memory so
+// both requests can send it. File parts backed by a Reader cannot be shared,
+// so executing a clone of a request with such a part fails instead of sending
+// an empty file. The http.Client stays shared; changing the
+// timeout, proxy or TLS settings of either request copies it first. Clone
+// does not change the request, so it may be called from several goroutines.
+func (r *Request) Clone() *Request {
+	clone := *r
+	clone.header = r.header.Clone()
+	clone.query = cloneValues(r.query)
+	clone.form = cloneValues(r.form)
+	clone.params = cloneValues(r.params)
+	clone.cookies = make([]*http.Cookie, 0, len(r.cookies))
+	for _, cookie := range r.cookies {
+		c := *cookie
+		clone.cookies = append(clone.cookies, &c)
+	}
+	clone.files = make([]*FilePart, 0, len(r.files))
+	for _, part := range r.files {
+		p := *part
+		p.Header = textproto.MIMEHeader(http.Header(part.Header).Clone())
+		clone.files = append(clone.files, &p)
+		if part.Reader != nil && clone.err == nil {
+			clone.err = fmt.Errorf("greq: file part %q is read from a Reader and cannot be sent by a clone", part.FieldName)
+		}
+	}
+	if r.body != nil {
+		body, err := r.body.copy()
+		if err != nil && clone.err == nil {
+			clone.err = err
+		}
+		clone.body = body
+	}
+	clone.expect = r.expect.clone()
+	clone.pathParams = make(map[string]string, len(r.pathParams))
+	for key, value := range r.pathParams {
+		clone.pathParams[key] = value
+	}
+	clone.middlewares = append([]Middleware(nil), r.middlewares...)
+	return &clone
+}
+
+func addValues(dst, src url.Values) {
+	for key, values := range src {
+		dst[key] = append(dst[key], values...)
+	}
+}
+
+func cloneValues(values url.Values) url.Values {
+	if values == nil {
+		return nil
+	}
+	return url.Values(http.Header(values).Clone())
+}
+
 func (r *Request) Do() (*http.Response, error) {
+	resp := r.Exec()
+	return resp.resp, resp.err
+}
+
+// prepare resolves the target URL, the header and the body of the request
+// without opening the body. multipart is set when the body is built from
+// attached files.
+func (r *Request) prepare() (target string, header http.Header, body *payload, multipart *multipartBody, err error) {
 	if r.err != nil {
-		return nil, r.err
+		return "", nil, nil, nil, r.err
 	}
-	var (
-		req      *http.Request
-		err      error
-		body     io.Reader
-		rawQuery string
-	)
-	if r.method == http.MethodGet || r.method == http.MethodHead {
-		if len(r.params) > 0 {
-			rawQuery = r.params.Encode()
-		}
+	query := url.Values{}
+	header = r.header.Clone()
+	if target, err = r.url(); err != nil {
+		return "", nil, nil, nil, err
+	}
+	if header == nil {
+		header = http.Header{}
+	}
+	// Query values always go to the URL. The body is, in order of precedence,
+	// the body set with SetBody and friends, a multipart body when files are
+	// attached, or the urlencoded form fields.
+	getOrHead := r.method == http.MethodGet || r.method == http.MethodHead
+	form := url.Values{}
+	addValues(query, r.query)
+	addValues(form, r.form)
+	if getOrHead || r.body != nil {
+		addValues(query, r.params)
 	} else {
-		if r.body != nil {
-			body = r.body
-			if len(r.params) > 0 {
-				rawQuery = r.params.Encode()
-			}
-		} else if r.file != nil {
-			file, err := os.Open(r.file.path)
-			if err != nil {
-				return nil, err
-			}
-			defer file.Close()
-			bodyBuf := &bytes.Buffer{}
-			bodyWriter := multipart.NewWriter(bodyBuf)
-			fileWriter, err := bodyWriter.CreateFormFile(r.file.name, r.file.filename)
-			if err != nil {
-				return nil, err
-			}
-			_, err = io.Copy(fileWriter, file)
-			if err != nil {
-				return nil, err
-			}
-			r.SetContentType(bodyWriter.FormDataContentType())
-			for key, values := range r.params {
-				for _, value := range values {
-					_ = bodyWriter.WriteField(key, value)
-				}
-			}
-			err = bodyWriter.Close()
-			if err != nil {
-				return nil, err
-			}
-			body = bodyBuf
-		} else if r.params != nil {
-			body = strings.NewReader(r.params.Encode())
+		addValues(form, r.params)
+	}
+	switch {
+	case r.body != nil:
+		if len(r.form) > 0 {
+			return "", nil, nil, nil, errors.New("greq: form fields cannot be sent with a request body")
+		}
+		body = r.body
+	case len(r.files) > 0:
+		if multipart, err = newMultipartBody(r.files, form); err != nil {
+			return "", nil, nil, nil, err
 		}
+		header.Set("Content-Type", multipart.contentType())
+		body = multipart.payload()
+	case !getOrHead || len(form) > 0:
+		body = &payload{data: []byte(form.Encode())}
 	}
 
-	if rawQuery != "" {
-		if strings.IndexByte(r.target, '?') == -1 {
-			r.target = r.target + "?" + rawQuery
+	if rawQuery := query.Encode(); rawQuery != "" {
+		if strings.IndexByte(target, '?') == -1 {
+			target = target + "?" + rawQuery
 		} else {
-			r.target = r.target + "&" + r.target
-		}
-	}
-	if r.proxy != "" {
-		u, err := url.Parse(r.proxy)
-		if err != nil {
-			return nil, err
+			target = target + "&" + rawQuery
 		}
-		transport := r.getTransport()
-		transport.Proxy = http.ProxyURL(u)
 	}
 
-	req, err = http.NewRequest(r.method, r.target, body)
+	return target, header, body, multipart, nil
+}
+
+func (r *Request) build() (*http.Request, error) {
+	req, _, _, err := r.buildBody()
+	return req, err
+}
+
+// buildBody builds the request and also returns the body it sends.
+func (r *Request) buildBody() (*http.Request, *payload, *multipartBody, error) {
+	target, header, body, multipart, err := r.prepare()
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
+	}
+	req, err := body.newHTTPRequest(r.method, target)
+	if err != nil {
+		return nil, nil, nil, err
 	}
 
+	ctx := req.Context()
 	if r.ctx != nil {
-		req = req.WithContext(r.ctx)
+		ctx = r.ctx
 	}
-	req.Header = r.header
+	req = req.WithContext(context.WithValue(ctx, routeKey{}, routeTemplate(r.target)))
+	req.Header = header
 
 	if len(r.cookies) > 0 {
 		for _, cookie := range r.cookies {
 			req.AddCookie(cookie)
 		}
 	}
-	r.req = req
-	return r.GetClient().Do(req)
+	return req, body, multipart, nil
 }
 
 func (r *Request) Exec() *Response {
 	before := time.Now()
-	resp, err := r.Do()
-	after := time.Now()
-	took := after.Sub(before)
-	return &Response{req: r.req, resp: resp, took: took, ctx: r.ctx, err: err}
+	response := &Response{ctx: r.ctx}
+	response.req, response.body, response.multipart, response.err = r.buildBody()
+	if response.err == nil {
+		response.client = r.GetClient()
+		response.captureBody()
+		response.resp, response.attempts, response.err = r.send(response.req, r.handler(response.client, response))
+	}
+	if response.err == nil && r.expect != nil {
+		response.err = r.expect.check(response)
+	}
+	response.took = time.Since(before)
+	return response
 }
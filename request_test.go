@@ -413,3 +413,35 @@ func Benchmark_withJson(b *testing.B) {
 		fmt.Printf("body = %s \n", body)
 	}
 }
+
+func TestQueryAndFormFields(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("r.ParseForm error, got = %s", err.Error())
+		}
+		w.Write([]byte(r.URL.RawQuery + "|" + r.PostForm.Encode()))
+	}
+	ts := server(handler)
+	defer ts.Close()
+
+	req := NewRequest("post", ts.URL)
+	req.SetQuery("page", "1")
+	req.AddQuery("tag", "a")
+	req.AddQuery("tag", "b")
+	req.SetFormField("name", "luffy")
+	req.SetParam("foo", "bar")
+	body, err := req.Exec().ToString()
+	if err != nil {
+		t.Errorf("req.exec error err= %s", err.Error())
+	}
+	if want := "page=1&tag=a&tag=b|foo=bar&name=luffy"; body != want {
+		t.Errorf("got body = %s, want = %s", body, want)
+	}
+
+	req = NewRequest("post", ts.URL)
+	req.SetFormField("name", "luffy")
+	req.SetBody("raw")
+	if err := req.Exec().Error(); err == nil {
+		t.Errorf("req.exec error got = nil, want form fields with body error")
+	}
+}
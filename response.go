@@ -7,23 +7,37 @@
 package greq
 
 import (
+	"bufio"
 	"bytes"
 	"context"
-	"encoding/json"
-	"encoding/xml"
+	"errors"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httputil"
+	"os"
+	"path/filepath"
 	"time"
 )
 
+var ErrBodyStreamed = errors.New("greq: response body has already been streamed")
+
 type Response struct {
 	req      *http.Request
 	resp     *http.Response
 	respBody []byte
 	took     time.Duration
+	attempts []time.Duration
+	timings  *timingRecorder
 	ctx      context.Context
 	err      error
+	streamed bool
+
+	// The client and body the request was sent with, for CurlCommand.
+	client    *http.Client
+	body      *payload
+	multipart *multipartBody
+	sent      *sentBody
 }
 
 func (r *Response) Error() error {
@@ -34,6 +48,15 @@ func (r *Response) Took() time.Duration {
 	return r.took
 }
 
+// Attempts returns how many times the request was sent, retries included.
+func (r *Response) Attempts() int {
+	return len(r.attempts)
+}
+
+func (r *Response) AttemptsTook() []time.Duration {
+	return r.attempts
+}
+
 func (r *Response) Request() *http.Request {
 	return r.req
 }
@@ -60,6 +83,9 @@ func (r *Response) ToBytes() ([]byte, error) {
 	if r.respBody != nil {
 		return r.respBody, nil
 	}
+	if r.streamed {
+		return nil, ErrBodyStreamed
+	}
 	defer r.resp.Body.Close()
 	body, err := ioutil.ReadAll(r.resp.Body)
 	if err != nil {
@@ -76,13 +102,7 @@ func (r *Response) ToString() (string, error) {
 }
 
 func (r *Response) ToJSON(v interface{}) error {
-	b, err := r.ToBytes()
-	if err != nil {
-		return err
-	}
-	d := json.NewDecoder(bytes.NewReader(b))
-	d.UseNumber()
-	return d.Decode(v)
+	return r.decodeJSON(v)
 }
 
 func (r *Response) ToXML(v interface{}) error {
@@ -90,7 +110,8 @@ func (r *Response) ToXML(v interface{}) error {
 	if err != nil {
 		return err
 	}
-	return xml.Unmarshal(bs, v)
+	codec, _ := LookupCodec(TypeXML)
+	return codec.Unmarshal(bs, v)
 }
 
 func (r *Response) Cookies() []*http.Cookie {
@@ -114,3 +135,90 @@ func (r *Response) DumpRequest(body bool) ([]byte, error) {
 func (r *Response) DumpResponse(body bool) ([]byte, error) {
 	return httputil.DumpResponse(r.resp, body)
 }
+
+// Stream returns the response body without buffering it. The caller must
+// close it. Reads fail once the request's context is done.
+func (r *Response) Stream() (io.ReadCloser, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	if r.respBody != nil {
+		return ioutil.NopCloser(bytes.NewReader(r.respBody)), nil
+	}
+	if r.streamed {
+		return nil, ErrBodyStreamed
+	}
+	r.streamed = true
+	return &ctxReader{ctx: r.ctx, ReadCloser: r.resp.Body}, nil
+}
+
+// SaveToFile writes the response body to a temporary file next to path and
+// renames it into place, so path never holds a partial download.
+func (r *Response) SaveToFile(path string) error {
+	body, err := r.Stream()
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	tmp, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = io.Copy(tmp, body); err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), 0644)
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Lines calls fn for every line of the response body, without the trailing
+// line break. It stops at the first error returned by fn.
+func (r *Response) Lines(fn func(line []byte) error) error {
+	body, err := r.Stream()
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	reader := bufio.NewReader(body)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if r.ctx != nil && r.ctx.Err() != nil {
+			return r.ctx.Err()
+		}
+		if len(line) > 0 {
+			line = bytes.TrimSuffix(bytes.TrimSuffix(line, []byte("\n")), []byte("\r"))
+			if fnErr := fn(line); fnErr != nil {
+				return fnErr
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+type ctxReader struct {
+	ctx context.Context
+	io.ReadCloser
+}
+
+func (r *ctxReader) Read(p []byte) (int, error) {
+	if r.ctx != nil {
+		if err := r.ctx.Err(); err != nil {
+			return 0, err
+		}
+	}
+	return r.ReadCloser.Read(p)
+}
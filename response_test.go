@@ -0,0 +1,95 @@
+/**
+* Created by GoLand.
+* User: luffy
+* Date: 2026-10-18
+* Time: 15:30
+ */
+package greq
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestResponseLines(t *testing.T) {
+	ts := server(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("{\"id\":1}\r\n{\"id\":2}\n{\"id\":3}"))
+	})
+	defer ts.Close()
+
+	resp := NewRequest("get", ts.URL).Exec()
+	var lines []string
+	err := resp.Lines(func(line []byte) error {
+		lines = append(lines, string(line))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("resp.Lines error, err = %s", err.Error())
+	}
+	want := []string{`{"id":1}`, `{"id":2}`, `{"id":3}`}
+	if !reflect.DeepEqual(lines, want) {
+		t.Errorf("lines got = %v, want = %v", lines, want)
+	}
+	if _, err := resp.ToBytes(); !errors.Is(err, ErrBodyStreamed) {
+		t.Errorf("resp.ToBytes error got = %v, want = %v", err, ErrBodyStreamed)
+	}
+}
+
+func TestResponseLinesContext(t *testing.T) {
+	ts := server(func(w http.ResponseWriter, r *http.Request) {
+		for i := 0; i < 100; i++ {
+			w.Write([]byte("line\n"))
+		}
+	})
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req := NewRequest("get", ts.URL)
+	req.SetContext(ctx)
+	resp := req.Exec()
+	count := 0
+	err := resp.Lines(func(line []byte) error {
+		count++
+		cancel()
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("resp.Lines error got = %v, want = %v", err, context.Canceled)
+	}
+	if count >= 100 {
+		t.Errorf("lines read got = %d, want fewer than 100", count)
+	}
+}
+
+func TestResponseSaveToFile(t *testing.T) {
+	ts := server(nil)
+	defer ts.Close()
+	dir, err := ioutil.TempDir("", "greq")
+	if err != nil {
+		t.Fatalf("ioutil.TempDir error, err = %s", err.Error())
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "hello.txt")
+	if err := NewRequest("get", ts.URL).Exec().SaveToFile(path); err != nil {
+		t.Fatalf("resp.SaveToFile error, err = %s", err.Error())
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ioutil.ReadFile error, err = %s", err.Error())
+	}
+	if string(data) != "Hello luffy !!" {
+		t.Errorf("file got = %s, want = Hello luffy !!", data)
+	}
+	files, _ := ioutil.ReadDir(dir)
+	if len(files) != 1 {
+		t.Errorf("files in dir got = %d, want = 1", len(files))
+	}
+}
@@ -0,0 +1,179 @@
+/**
+* Created by GoLand.
+* User: luffy
+* Date: 2026-10-18
+* Time: 11:10
+ */
+package greq
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryCondition reports whether an attempt that returned resp or err should
+// be retried.
+type RetryCondition func(resp *http.Response, err error) bool
+
+// RetryPolicy configures automatic retries. MaxAttempts counts the first
+// attempt too; backoff grows exponentially from WaitMin up to WaitMax with
+// full jitter, and a Retry-After header overrides it (still capped by
+// WaitMax).
+type RetryPolicy struct {
+	MaxAttempts int
+	WaitMin     time.Duration
+	WaitMax     time.Duration
+	Conditions  []RetryCondition
+}
+
+func NewRetryPolicy(maxAttempts int, conditions ...RetryCondition) *RetryPolicy {
+	if len(conditions) == 0 {
+		conditions = []RetryCondition{RetryOnServerError, RetryOnTooManyRequests, RetryOnTimeout}
+	}
+	return &RetryPolicy{
+		MaxAttempts: maxAttempts,
+		WaitMin:     100 * time.Millisecond,
+		WaitMax:     10 * time.Second,
+		Conditions:  conditions,
+	}
+}
+
+func RetryOnStatus(codes ...int) RetryCondition {
+	return func(resp *http.Response, err error) bool {
+		if resp == nil {
+			return false
+		}
+		for _, code := range codes {
+			if resp.StatusCode == code {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func RetryOnServerError(resp *http.Response, err error) bool {
+	return resp != nil && resp.StatusCode >= 500 && resp.StatusCode != http.StatusNotImplemented
+}
+
+func RetryOnTooManyRequests(resp *http.Response, err error) bool {
+	return resp != nil && resp.StatusCode == http.StatusTooManyRequests
+}
+
+func RetryOnTimeout(resp *http.Response, err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+func RetryOnNetworkError(resp *http.Response, err error) bool {
+	return err != nil && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
+
+func (p *RetryPolicy) shouldRetry(resp *http.Response, err error) bool {
+	for _, condition := range p.Conditions {
+		if condition(resp, err) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *RetryPolicy) backoff(attempt int, resp *http.Response) time.Duration {
+	if wait, ok := retryAfter(resp); ok {
+		if p.WaitMax > 0 && wait > p.WaitMax {
+			wait = p.WaitMax
+		}
+		return wait
+	}
+	wait := p.WaitMin << uint(attempt-1)
+	if wait <= 0 || (p.WaitMax > 0 && wait > p.WaitMax) {
+		wait = p.WaitMax
+	}
+	if wait <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(wait) + 1))
+}
+
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
+
+func (r *Request) SetRetry(policy *RetryPolicy) {
+	r.retry = policy
+}
+
+func (c *Client) SetRetry(policy *RetryPolicy) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.retry = policy
+}
+
+// send executes req through handler, retrying according to the request's
+// RetryPolicy, and returns the duration of every attempt.
+func (r *Request) send(req *http.Request, handler Handler) (*http.Response, []time.Duration, error) {
+	var took []time.Duration
+	for attempt := 1; ; attempt++ {
+		before := time.Now()
+		resp, err := handler(req)
+		took = append(took, time.Since(before))
+
+		policy := r.retry
+		if policy == nil || attempt >= policy.MaxAttempts || !policy.shouldRetry(resp, err) {
+			return resp, took, err
+		}
+		if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+			return resp, took, err
+		}
+		wait := policy.backoff(attempt, resp)
+		if resp != nil {
+			_, _ = io.CopyN(ioutil.Discard, resp.Body, 4<<10)
+			resp.Body.Close()
+		}
+		if err := sleep(req.Context(), wait); err != nil {
+			return nil, took, err
+		}
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, took, err
+			}
+			req.Body = body
+		}
+	}
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,90 @@
+/**
+* Created by GoLand.
+* User: luffy
+* Date: 2026-10-18
+* Time: 11:45
+ */
+package greq
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryRewindsBody(t *testing.T) {
+	var calls int32
+	ts := server(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if string(body) != "payload" {
+			t.Errorf("got body %s, want payload", body)
+		}
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok"))
+	})
+	defer ts.Close()
+
+	req := NewRequest("post", ts.URL)
+	req.SetBody(strings.NewReader("payload"))
+	policy := NewRetryPolicy(5)
+	policy.WaitMin = time.Millisecond
+	req.SetRetry(policy)
+	resp := req.Exec()
+	if err := resp.Error(); err != nil {
+		t.Fatalf("req.exec error err= %s", err.Error())
+	}
+	if resp.StatusCode() != 200 {
+		t.Errorf("req.exec statuscode want = 200, got = %d", resp.StatusCode())
+	}
+	if resp.Attempts() != 3 || len(resp.AttemptsTook()) != 3 {
+		t.Errorf("attempts got = %d, want = 3", resp.Attempts())
+	}
+}
+
+func TestRetryAfterAndMaxAttempts(t *testing.T) {
+	var calls int32
+	ts := server(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	defer ts.Close()
+
+	req := NewRequest("get", ts.URL)
+	req.SetRetry(NewRetryPolicy(3, RetryOnTooManyRequests))
+	resp := req.Exec()
+	if resp.StatusCode() != http.StatusTooManyRequests {
+		t.Errorf("req.exec statuscode want = 429, got = %d", resp.StatusCode())
+	}
+	if n := atomic.LoadInt32(&calls); n != 3 || resp.Attempts() != 3 {
+		t.Errorf("calls got = %d, attempts got = %d, want = 3", n, resp.Attempts())
+	}
+}
+
+func TestRetryStopsOnContextCancel(t *testing.T) {
+	ts := server(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	})
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req := NewRequest("get", ts.URL)
+	req.SetContext(ctx)
+	policy := NewRetryPolicy(10)
+	policy.WaitMin = time.Second
+	policy.WaitMax = time.Second
+	req.SetRetry(policy)
+	resp := req.Exec()
+	if !errors.Is(resp.Error(), context.DeadlineExceeded) {
+		t.Errorf("req.exec error got = %v, want = %v", resp.Error(), context.DeadlineExceeded)
+	}
+}
@@ -0,0 +1,298 @@
+/**
+* Created by GoLand.
+* User: luffy
+* Date: 2026-10-19
+* Time: 16:00
+ */
+package greq
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Signer signs a request. It runs for every attempt after all middlewares,
+// so it sees the final URL, headers and body.
+type Signer interface {
+	Sign(req *http.Request) error
+}
+
+// SignerFunc adapts a function to the Signer interface.
+type SignerFunc func(req *http.Request) error
+
+func (f SignerFunc) Sign(req *http.Request) error {
+	return f(req)
+}
+
+func (r *Request) SetSigner(signer Signer) {
+	r.signer = signer
+}
+
+func (c *Client) SetSigner(signer Signer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.signer = signer
+}
+
+func signing(signer Signer, next Handler) Handler {
+	return func(req *http.Request) (*http.Response, error) {
+		if err := signer.Sign(req); err != nil {
+			return nil, err
+		}
+		return next(req)
+	}
+}
+
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// PayloadHash returns the hex encoded SHA-256 of the request body, read
+// through GetBody so the body itself is left untouched.
+func PayloadHash(req *http.Request) (string, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return emptyPayloadHash, nil
+	}
+	if req.GetBody == nil {
+		return "", errors.New("greq: signing a request needs a replayable body")
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, body); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// AWSSigner signs requests with AWS Signature Version 4. It signs the host,
+// Content-Type, Content-MD5 and every X-Amz-* header. For S3 it also sets
+// X-Amz-Content-Sha256, and UnsignedPayload skips hashing the body.
+type AWSSigner struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	Region          string
+	Service         string
+	UnsignedPayload bool
+	Now             func() time.Time
+}
+
+func (s *AWSSigner) Sign(req *http.Request) error {
+	now := time.Now
+	if s.Now != nil {
+		now = s.Now
+	}
+	t := now().UTC()
+	amzDate := t.Format("20060102T150405Z")
+	date := t.Format("20060102")
+
+	hash := "UNSIGNED-PAYLOAD"
+	if !s.UnsignedPayload {
+		var err error
+		if hash, err = PayloadHash(req); err != nil {
+			return err
+		}
+	}
+	req.Header.Del("Authorization")
+	req.Header.Set("X-Amz-Date", amzDate)
+	if s.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.SessionToken)
+	}
+	if s.Service == "s3" {
+		req.Header.Set("X-Amz-Content-Sha256", hash)
+	}
+
+	headers := map[string]string{"host": requestHost(req)}
+	for key, values := range req.Header {
+		name := strings.ToLower(key)
+		if name == "content-type" || name == "content-md5" || strings.HasPrefix(name, "x-amz-") {
+			headers[name] = canonicalHeaderValue(values)
+		}
+	}
+	signedHeaders, canonicalHeaders := canonicalizeHeaders(headers)
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalPath(req.URL.Path, s.Service == "s3"),
+		canonicalQuery(req),
+		canonicalHeaders,
+		signedHeaders,
+		hash,
+	}, "\n")
+
+	scope := strings.Join([]string{date, s.Region, s.Service, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hex.EncodeToString(sha256Sum([]byte(canonicalRequest))),
+	}, "\n")
+	key := []byte("AWS4" + s.SecretAccessKey)
+	for _, part := range []string{date, s.Region, s.Service, "aws4_request"} {
+		key = hmacSHA256(key, []byte(part))
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, []byte(stringToSign)))
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.AccessKeyID, scope, signedHeaders, signature))
+	return nil
+}
+
+// HMACSigner signs requests with HMAC-SHA256 over a canonical string. By
+// default the canonical string is
+//
+//	METHOD \n escaped path \n sorted query \n X-Date \n name:value of Headers... \n body SHA-256
+//
+// and the signature is sent as
+//
+//	Authorization: HMAC-SHA256 KeyId=<KeyID>, SignedHeaders=x-date;..., Signature=<base64>
+//
+// Canonicalize and Format replace either part.
+type HMACSigner struct {
+	KeyID   string
+	Secret  []byte
+	Headers []string
+	// Canonicalize builds the string to sign from the request and the hex
+	// SHA-256 of its body.
+	Canonicalize func(req *http.Request, payloadHash string) (string, error)
+	// Format sets the signature on the request.
+	Format func(req *http.Request, keyID string, signature []byte)
+	Now    func() time.Time
+}
+
+func (s *HMACSigner) Sign(req *http.Request) error {
+	now := time.Now
+	if s.Now != nil {
+		now = s.Now
+	}
+	req.Header.Set("X-Date", now().UTC().Format(http.TimeFormat))
+	hash, err := PayloadHash(req)
+	if err != nil {
+		return err
+	}
+	canonicalize := s.Canonicalize
+	if canonicalize == nil {
+		canonicalize = s.canonicalize
+	}
+	canonical, err := canonicalize(req, hash)
+	if err != nil {
+		return err
+	}
+	signature := hmacSHA256(s.Secret, []byte(canonical))
+	if s.Format != nil {
+		s.Format(req, s.KeyID, signature)
+		return nil
+	}
+	signed := []string{"x-date"}
+	for _, name := range s.Headers {
+		signed = append(signed, strings.ToLower(name))
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("HMAC-SHA256 KeyId=%s, SignedHeaders=%s, Signature=%s",
+		s.KeyID, strings.Join(signed, ";"), base64.StdEncoding.EncodeToString(signature)))
+	return nil
+}
+
+func (s *HMACSigner) canonicalize(req *http.Request, payloadHash string) (string, error) {
+	lines := []string{req.Method, req.URL.EscapedPath(), canonicalQuery(req), req.Header.Get("X-Date")}
+	for _, name := range s.Headers {
+		value := req.Header.Values(name)
+		if strings.EqualFold(name, "host") {
+			value = []string{requestHost(req)}
+		}
+		lines = append(lines, strings.ToLower(name)+":"+canonicalHeaderValue(value))
+	}
+	lines = append(lines, payloadHash)
+	return strings.Join(lines, "\n"), nil
+}
+
+func requestHost(req *http.Request) string {
+	if req.Host != "" {
+		return req.Host
+	}
+	return req.URL.Host
+}
+
+func canonicalHeaderValue(values []string) string {
+	trimmed := make([]string, len(values))
+	for i, value := range values {
+		trimmed[i] = strings.Join(strings.Fields(value), " ")
+	}
+	return strings.Join(trimmed, ",")
+}
+
+func canonicalizeHeaders(headers map[string]string) (string, string) {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	for _, name := range names {
+		b.WriteString(name + ":" + headers[name] + "\n")
+	}
+	return strings.Join(names, ";"), b.String()
+}
+
+func canonicalQuery(req *http.Request) string {
+	query := req.URL.Query()
+	pairs := make([]string, 0, len(query))
+	for key, values := range query {
+		for _, value := range values {
+			pairs = append(pairs, awsEscape(key)+"="+awsEscape(value))
+		}
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "&")
+}
+
+// canonicalPath encodes every segment of the decoded path once for S3 and
+// twice for other services, as SigV4 requires.
+func canonicalPath(path string, s3 bool) string {
+	if path == "" {
+		return "/"
+	}
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = awsEscape(segment)
+		if !s3 {
+			segments[i] = awsEscape(segments[i])
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// awsEscape percent-encodes everything but the RFC 3986 unreserved
+// characters.
+func awsEscape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func sha256Sum(data []byte) []byte {
+	sum := sha256.Sum256(data)
+	return sum[:]
+}
+
+func hmacSHA256(key, data []byte) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write(data)
+	return h.Sum(nil)
+}
@@ -0,0 +1,118 @@
+/**
+* Created by GoLand.
+* User: luffy
+* Date: 2026-10-19
+* Time: 16:40
+ */
+package greq
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAWSSigner(t *testing.T) {
+	// get-vanilla from the AWS SigV4 test suite.
+	signer := &AWSSigner{
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+		Region:          "us-east-1",
+		Service:         "service",
+		Now: func() time.Time {
+			return time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+		},
+	}
+	req, _ := http.NewRequest("GET", "https://example.amazonaws.com/", nil)
+	if err := signer.Sign(req); err != nil {
+		t.Fatalf("signer.Sign error, err = %s", err.Error())
+	}
+	want := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+		"SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"
+	if got := req.Header.Get("Authorization"); got != want {
+		t.Errorf("authorization got = %s, want = %s", got, want)
+	}
+	if req.Header.Get("X-Amz-Content-Sha256") != "" {
+		t.Errorf("x-amz-content-sha256 is only set for s3")
+	}
+
+	// get-unreserved from the AWS SigV4 test suite.
+	req, _ = http.NewRequest("GET", "https://example.amazonaws.com/-._~0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz", nil)
+	if err := signer.Sign(req); err != nil {
+		t.Fatalf("signer.Sign error, err = %s", err.Error())
+	}
+	want = "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+		"SignedHeaders=host;x-amz-date, Signature=07ef7494c76fa4850883e2b006601f940f8a34d404d0cfa977f52a65bbf5f24f"
+	if got := req.Header.Get("Authorization"); got != want {
+		t.Errorf("authorization got = %s, want = %s", got, want)
+	}
+}
+
+func TestAWSCanonicalPath(t *testing.T) {
+	for _, tc := range []struct {
+		url  string
+		s3   bool
+		want string
+	}{
+		{"https://example.com", false, "/"},
+		{"https://bucket.s3.amazonaws.com/bucket/dt=2024-01-01/a+b", true, "/bucket/dt%3D2024-01-01/a%2Bb"},
+		{"https://bucket.s3.amazonaws.com/a%20b/(x)!*,;:@$", true, "/a%20b/%28x%29%21%2A%2C%3B%3A%40%24"},
+		{"https://example.amazonaws.com/example space/", false, "/example%2520space/"},
+		{"https://example.amazonaws.com/\u1234/a=b", false, "/%25E1%2588%25B4/a%253Db"},
+	} {
+		u, err := url.Parse(tc.url)
+		if err != nil {
+			t.Fatalf("url.Parse error, err = %s", err.Error())
+		}
+		if got := canonicalPath(u.Path, tc.s3); got != tc.want {
+			t.Errorf("canonicalPath(%s) got = %s, want = %s", tc.url, got, tc.want)
+		}
+	}
+}
+
+func TestHMACSigner(t *testing.T) {
+	secret := []byte("secret")
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		canonical := strings.Join([]string{r.Method, r.URL.EscapedPath(), "a=1&b=2", r.Header.Get("X-Date"),
+			"x-tenant:acme", hashHex(sha256.New, string(body))}, "\n")
+		mac := hmac.New(sha256.New, secret)
+		mac.Write([]byte(canonical))
+		want := "HMAC-SHA256 KeyId=key, SignedHeaders=x-date;x-tenant, Signature=" + base64.StdEncoding.EncodeToString(mac.Sum(nil))
+		if r.Header.Get("Authorization") != want {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write(body)
+	}
+	ts := server(handler)
+	defer ts.Close()
+
+	client := NewClient()
+	client.SetBaseURL(ts.URL)
+	client.SetSigner(&HMACSigner{KeyID: "key", Secret: secret, Headers: []string{"X-Tenant"}})
+	client.SetRetry(NewRetryPolicy(2, RetryOnStatus(http.StatusTeapot)))
+	// The tenant header is added by a middleware, after which the signer runs.
+	client.Use(func(next Handler) Handler {
+		return func(req *http.Request) (*http.Response, error) {
+			req.Header.Set("X-Tenant", "acme")
+			return next(req)
+		}
+	})
+	req := client.NewRequest("post", "/orders?b=2")
+	req.SetQuery("a", "1")
+	req.SetBody("payload")
+	resp := req.Exec()
+	if resp.StatusCode() != http.StatusOK {
+		t.Fatalf("req.exec statuscode want = 200, got = %d", resp.StatusCode())
+	}
+	if body, _ := resp.ToString(); body != "payload" {
+		t.Errorf("got body = %s, want = payload", body)
+	}
+}
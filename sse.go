@@ -0,0 +1,171 @@
+/**
+* Created by GoLand.
+* User: luffy
+* Date: 2026-10-18
+* Time: 16:10
+ */
+package greq
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const TypeEventStream = "text/event-stream"
+
+// Event is a server-sent event.
+type Event struct {
+	ID    string
+	Event string
+	Data  string
+	Retry time.Duration
+}
+
+// SSE connects to a text/event-stream endpoint and calls handler for every
+// event. When the stream ends or the connection fails it reconnects with the
+// Last-Event-ID header, waiting for the retry interval sent by the server
+// (3 seconds by default). It returns when handler returns an error, the
+// request's context is done, the server answers 204 No Content or the server
+// answers with something other than an event stream.
+func (r *Request) SSE(handler func(event *Event) error) error {
+	req, err := r.build()
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", TypeEventStream)
+	req.Header.Set("Cache-Control", "no-cache")
+	// The client timeout covers reading the body, which would cut long-lived
+	// streams; the request's context bounds the connection instead.
+	client := *r.GetClient()
+	client.Timeout = 0
+	stream := &eventStream{
+		request: r,
+		req:     req,
+		handler: r.handler(&client, nil),
+		retry:   3 * time.Second,
+	}
+	for {
+		reconnect, err := stream.connect(handler)
+		if !reconnect {
+			return err
+		}
+		if err := sleep(req.Context(), stream.retry); err != nil {
+			return err
+		}
+	}
+}
+
+type eventStream struct {
+	request     *Request
+	req         *http.Request
+	handler     Handler
+	lastEventID string
+	retry       time.Duration
+}
+
+func (s *eventStream) connect(handler func(event *Event) error) (bool, error) {
+	ctx := s.req.Context()
+	req := s.req.Clone(ctx)
+	if s.req.GetBody != nil {
+		body, err := s.req.GetBody()
+		if err != nil {
+			return false, err
+		}
+		req.Body = body
+	}
+	if s.lastEventID != "" {
+		req.Header.Set("Last-Event-ID", s.lastEventID)
+	}
+	resp, _, err := s.request.send(req, s.handler)
+	if err != nil {
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+		return true, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNoContent {
+		return false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, int64(MaxErrorBodySize)))
+		return false, newHTTPError(req, resp, body)
+	}
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType != TypeEventStream {
+		return false, fmt.Errorf("greq: event stream %s returned content type %q", req.URL, resp.Header.Get("Content-Type"))
+	}
+	err = s.read(resp.Body, handler)
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+	if e, ok := err.(handlerError); ok {
+		return false, e.err
+	}
+	return true, err
+}
+
+type handlerError struct {
+	err error
+}
+
+func (e handlerError) Error() string {
+	return e.err.Error()
+}
+
+func (s *eventStream) read(body io.Reader, handler func(event *Event) error) error {
+	reader := bufio.NewReader(body)
+	event := &Event{}
+	var data strings.Builder
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return err
+		}
+		line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+		if line == "" {
+			if data.Len() > 0 {
+				event.ID = s.lastEventID
+				event.Data = strings.TrimSuffix(data.String(), "\n")
+				if event.Event == "" {
+					event.Event = "message"
+				}
+				if err := handler(event); err != nil {
+					return handlerError{err: err}
+				}
+			}
+			event = &Event{}
+			data.Reset()
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+		field, value := line, ""
+		if i := strings.IndexByte(line, ':'); i >= 0 {
+			field, value = line[:i], strings.TrimPrefix(line[i+1:], " ")
+		}
+		switch field {
+		case "event":
+			event.Event = value
+		case "data":
+			data.WriteString(value)
+			data.WriteByte('\n')
+		case "id":
+			if !strings.ContainsRune(value, 0) {
+				s.lastEventID = value
+			}
+		case "retry":
+			if ms, err := strconv.Atoi(value); err == nil && ms >= 0 {
+				s.retry = time.Duration(ms) * time.Millisecond
+				event.Retry = s.retry
+			}
+		}
+	}
+}
@@ -0,0 +1,72 @@
+/**
+* Created by GoLand.
+* User: luffy
+* Date: 2026-10-18
+* Time: 16:45
+ */
+package greq
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSSEReconnect(t *testing.T) {
+	var conns int32
+	ts := server(func(w http.ResponseWriter, r *http.Request) {
+		if v := r.Header.Get("Accept"); v != TypeEventStream {
+			t.Errorf("header Accept got = %s, want = %s", v, TypeEventStream)
+		}
+		w.Header().Set("Content-Type", TypeEventStream)
+		switch atomic.AddInt32(&conns, 1) {
+		case 1:
+			w.Write([]byte(": comment\nretry: 10\nid: 1\nevent: greet\ndata: hello\ndata: world\n\nid: 2\ndata: second\n\n"))
+		default:
+			if v := r.Header.Get("Last-Event-ID"); v != "2" {
+				t.Errorf("header Last-Event-ID got = %s, want = 2", v)
+			}
+			w.Write([]byte("id: 3\r\ndata: third\r\n\r\n"))
+		}
+	})
+	defer ts.Close()
+
+	stop := errors.New("stop")
+	var events []Event
+	err := NewRequest("get", ts.URL).SSE(func(event *Event) error {
+		events = append(events, *event)
+		if len(events) == 3 {
+			return stop
+		}
+		return nil
+	})
+	if err != stop {
+		t.Errorf("req.SSE error got = %v, want = %v", err, stop)
+	}
+	want := []Event{
+		{ID: "1", Event: "greet", Data: "hello\nworld", Retry: 10 * time.Millisecond},
+		{ID: "2", Event: "message", Data: "second"},
+		{ID: "3", Event: "message", Data: "third"},
+	}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("events got = %+v, want = %+v", events, want)
+	}
+}
+
+func TestSSENoContent(t *testing.T) {
+	ts := server(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	defer ts.Close()
+
+	err := NewRequest("get", ts.URL).SSE(func(event *Event) error {
+		t.Errorf("unexpected event %+v", event)
+		return nil
+	})
+	if err != nil {
+		t.Errorf("req.SSE error got = %v, want = nil", err)
+	}
+}
@@ -0,0 +1,157 @@
+/**
+* Created by GoLand.
+* User: luffy
+* Date: 2026-10-19
+* Time: 22:00
+ */
+package greq
+
+import (
+	"crypto/tls"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// Timings are the phases of the last attempt of a request. Phases that did
+// not happen, such as DNS lookup and connect on a reused connection, are zero.
+// FirstByte and Total are measured from the start of the attempt;
+// ContentTransfer and Total are only known once the body has been read.
+type Timings struct {
+	DNSLookup        time.Duration
+	TCPConnect       time.Duration
+	TLSHandshake     time.Duration
+	ServerProcessing time.Duration
+	FirstByte        time.Duration
+	ContentTransfer  time.Duration
+	Total            time.Duration
+	ConnReused       bool
+	RemoteAddr       string
+}
+
+// Timings returns the phase timings of the last attempt.
+func (r *Response) Timings() Timings {
+	if r.timings == nil {
+		return Timings{}
+	}
+	return r.timings.timings()
+}
+
+type timingRecorder struct {
+	mu           sync.Mutex
+	start        time.Time
+	dnsStart     time.Time
+	dnsDone      time.Time
+	connectStart time.Time
+	connectDone  time.Time
+	tlsStart     time.Time
+	tlsDone      time.Time
+	wroteRequest time.Time
+	firstByte    time.Time
+	bodyDone     time.Time
+	reused       bool
+	remoteAddr   string
+}
+
+func (t *timingRecorder) set(at *time.Time) {
+	t.mu.Lock()
+	*at = time.Now()
+	t.mu.Unlock()
+}
+
+func (t *timingRecorder) trace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { t.set(&t.dnsStart) },
+		DNSDone:  func(httptrace.DNSDoneInfo) { t.set(&t.dnsDone) },
+		ConnectStart: func(string, string) {
+			t.mu.Lock()
+			// Dual stack dialing may start several connects; time the first.
+			if t.connectStart.IsZero() {
+				t.connectStart = time.Now()
+			}
+			t.mu.Unlock()
+		},
+		ConnectDone:          func(string, string, error) { t.set(&t.connectDone) },
+		TLSHandshakeStart:    func() { t.set(&t.tlsStart) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { t.set(&t.tlsDone) },
+		WroteRequest:         func(httptrace.WroteRequestInfo) { t.set(&t.wroteRequest) },
+		GotFirstResponseByte: func() { t.set(&t.firstByte) },
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.mu.Lock()
+			t.reused = info.Reused
+			if info.Conn != nil {
+				t.remoteAddr = info.Conn.RemoteAddr().String()
+			}
+			t.mu.Unlock()
+		},
+	}
+}
+
+func (t *timingRecorder) timings() Timings {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	timings := Timings{
+		DNSLookup:        between(t.dnsStart, t.dnsDone),
+		TCPConnect:       between(t.connectStart, t.connectDone),
+		TLSHandshake:     between(t.tlsStart, t.tlsDone),
+		ServerProcessing: between(t.wroteRequest, t.firstByte),
+		FirstByte:        between(t.start, t.firstByte),
+		ContentTransfer:  between(t.firstByte, t.bodyDone),
+		ConnReused:       t.reused,
+		RemoteAddr:       t.remoteAddr,
+	}
+	timings.Total = timings.FirstByte
+	if !t.bodyDone.IsZero() {
+		timings.Total = between(t.start, t.bodyDone)
+	}
+	return timings
+}
+
+func between(start, end time.Time) time.Duration {
+	if start.IsZero() || end.IsZero() {
+		return 0
+	}
+	return end.Sub(start)
+}
+
+// timing records the phases of every attempt; the response keeps the last.
+func (r *Response) timing(next Handler) Handler {
+	return func(req *http.Request) (*http.Response, error) {
+		recorder := &timingRecorder{start: time.Now()}
+		r.timings = recorder
+		resp, err := next(req.WithContext(httptrace.WithClientTrace(req.Context(), recorder.trace())))
+		if resp != nil {
+			resp.Body = &timedBody{ReadCloser: resp.Body, recorder: recorder}
+		}
+		return resp, err
+	}
+}
+
+type timedBody struct {
+	io.ReadCloser
+	recorder *timingRecorder
+}
+
+func (b *timedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err == io.EOF {
+		b.done()
+	}
+	return n, err
+}
+
+func (b *timedBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.done()
+	return err
+}
+
+func (b *timedBody) done() {
+	b.recorder.mu.Lock()
+	if b.recorder.bodyDone.IsZero() {
+		b.recorder.bodyDone = time.Now()
+	}
+	b.recorder.mu.Unlock()
+}
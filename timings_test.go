@@ -0,0 +1,52 @@
+/**
+* Created by GoLand.
+* User: luffy
+* Date: 2026-10-19
+* Time: 22:40
+ */
+package greq
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTimings(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		w.Write([]byte("hello "))
+		w.(http.Flusher).Flush()
+		time.Sleep(20 * time.Millisecond)
+		w.Write([]byte("world"))
+	}))
+	defer ts.Close()
+
+	client := NewClient()
+	client.EnableInsecureTLS(true)
+	resp := client.NewRequest("get", ts.URL).Exec()
+	if body, _ := resp.ToString(); body != "hello world" {
+		t.Fatalf("got body = %s, want = hello world", body)
+	}
+	timings := resp.Timings()
+	if timings.ConnReused || timings.TCPConnect <= 0 || timings.TLSHandshake <= 0 {
+		t.Errorf("first request timings got = %+v", timings)
+	}
+	if timings.ServerProcessing < 20*time.Millisecond || timings.ContentTransfer < 20*time.Millisecond {
+		t.Errorf("first request timings got = %+v", timings)
+	}
+	if timings.FirstByte < timings.ServerProcessing || timings.Total < timings.FirstByte+timings.ContentTransfer {
+		t.Errorf("first request timings got = %+v", timings)
+	}
+	if timings.Total > resp.Took()+timings.ContentTransfer {
+		t.Errorf("total = %s is longer than took = %s", timings.Total, resp.Took())
+	}
+
+	resp = client.NewRequest("get", ts.URL).Exec()
+	resp.ToString()
+	timings = resp.Timings()
+	if !timings.ConnReused || timings.TCPConnect != 0 || timings.TLSHandshake != 0 || timings.RemoteAddr != ts.Listener.Addr().String() {
+		t.Errorf("second request timings got = %+v", timings)
+	}
+}
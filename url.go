@@ -0,0 +1,69 @@
+/**
+* Created by GoLand.
+* User: luffy
+* Date: 2026-10-18
+* Time: 23:00
+ */
+package greq
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var pathParamPattern = regexp.MustCompile(`\{([^{}/]+)\}`)
+
+// SetPathParam sets the value of a {key} placeholder in the request target,
+// such as /users/{id}. Values are path-escaped.
+func (r *Request) SetPathParam(key, value string) {
+	if r.pathParams == nil {
+		r.pathParams = map[string]string{}
+	}
+	r.pathParams[key] = value
+}
+
+func (r *Request) SetPathParams(params map[string]string) {
+	for key, value := range params {
+		r.SetPathParam(key, value)
+	}
+}
+
+// url expands the path parameters of the target and resolves it against the
+// base URL. Placeholders without a value are an error.
+func (r *Request) url() (string, error) {
+	var missing []string
+	target := pathParamPattern.ReplaceAllStringFunc(r.target, func(placeholder string) string {
+		key := placeholder[1 : len(placeholder)-1]
+		value, ok := r.pathParams[key]
+		if !ok {
+			missing = append(missing, key)
+			return placeholder
+		}
+		return url.PathEscape(value)
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("greq: unresolved path parameters %s in %s", strings.Join(missing, ", "), r.target)
+	}
+	return joinURL(r.baseURL, target), nil
+}
+
+// joinURL resolves path against baseURL. Absolute URLs are used as they are;
+// anything else is appended to the base path, so with a base of
+// https://api.example.com/v1 the path /users means /v1/users.
+func joinURL(baseURL, path string) string {
+	if baseURL == "" {
+		return path
+	}
+	if u, err := url.Parse(path); err == nil && u.IsAbs() {
+		return path
+	}
+	if path == "" {
+		return baseURL
+	}
+	if strings.HasPrefix(path, "?") {
+		return baseURL + path
+	}
+	return strings.TrimRight(baseURL, "/") + "/" + strings.TrimLeft(path, "/")
+}
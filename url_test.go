@@ -0,0 +1,55 @@
+/**
+* Created by GoLand.
+* User: luffy
+* Date: 2026-10-18
+* Time: 23:30
+ */
+package greq
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestPathParams(t *testing.T) {
+	ts := server(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.URL.EscapedPath()))
+	})
+	defer ts.Close()
+
+	client := NewClient()
+	client.SetBaseURL(ts.URL + "/v1")
+	client.SetPathParam("tenant", "acme")
+	req := client.NewRequest("get", "/{tenant}/users/{id}/orders/{orderId}")
+	req.SetPathParams(map[string]string{"id": "42", "orderId": "a/b c"})
+	body, err := req.Exec().ToString()
+	if err != nil {
+		t.Fatalf("req.exec error err= %s", err.Error())
+	}
+	if want := "/v1/acme/users/42/orders/a%2Fb%20c"; body != want {
+		t.Errorf("path got = %s, want = %s", body, want)
+	}
+
+	err = client.NewRequest("get", "/users/{id}/orders/{orderId}").Exec().Error()
+	if err == nil || !strings.Contains(err.Error(), "id, orderId") {
+		t.Errorf("req.exec error got = %v, want unresolved path parameters", err)
+	}
+}
+
+func TestJoinURL(t *testing.T) {
+	for _, tc := range []struct {
+		base, path, want string
+	}{
+		{"", "http://a.com/x", "http://a.com/x"},
+		{"http://a.com/v1", "http://b.com/x", "http://b.com/x"},
+		{"http://a.com/v1", "/users", "http://a.com/v1/users"},
+		{"http://a.com/v1/", "users", "http://a.com/v1/users"},
+		{"http://a.com/v1", "", "http://a.com/v1"},
+		{"http://a.com/v1", "?page=2", "http://a.com/v1?page=2"},
+	} {
+		if got := joinURL(tc.base, tc.path); got != tc.want {
+			t.Errorf("joinURL(%q, %q) got = %s, want = %s", tc.base, tc.path, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,262 @@
+/**
+* Created by GoLand.
+* User: luffy
+* Date: 2026-10-19
+* Time: 09:40
+ */
+package greq
+
+import (
+	"encoding"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Encoder is implemented by types that encode themselves into url.Values.
+type Encoder interface {
+	EncodeValues(key string, v *url.Values) error
+}
+
+var (
+	encoderType       = reflect.TypeOf((*Encoder)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	timeType          = reflect.TypeOf(time.Time{})
+)
+
+// EncodeStruct encodes the exported fields of a struct into url.Values,
+// driven by `url` tags:
+//
+//	Name   string    `url:"name,omitempty"` // skipped when empty
+//	IDs    []int     `url:"id"`             // id=1&id=2
+//	Tags   []string  `url:"tags,comma"`     // tags=a,b
+//	Since  time.Time `url:"since,unix"`     // also unixmilli, or a layout tag
+//	Day    time.Time `url:"day" layout:"2006-01-02"`
+//	Secret string    `url:"-"`
+//
+// Fields of embedded structs are promoted; other nested structs are encoded
+// as parent[child]. Types implementing Encoder or encoding.TextMarshaler
+// encode themselves; times default to RFC 3339.
+func EncodeStruct(v interface{}) (url.Values, error) {
+	values := url.Values{}
+	val := reflect.ValueOf(v)
+	for val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return values, nil
+		}
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("greq: EncodeStruct expects a struct, got %T", v)
+	}
+	if !val.CanAddr() {
+		// Copy a struct passed by value so that fields whose Encoder or
+		// TextMarshaler has a pointer receiver can be addressed.
+		addressable := reflect.New(val.Type()).Elem()
+		addressable.Set(val)
+		val = addressable
+	}
+	return values, encodeStruct(values, val, "")
+}
+
+func encodeStruct(values url.Values, val reflect.Value, scope string) error {
+	typ := val.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+		tag := field.Tag.Get("url")
+		if tag == "-" {
+			continue
+		}
+		name, opts := parseTag(tag)
+		fv := val.Field(i)
+		if field.Anonymous && name == "" {
+			embedded := fv
+			for embedded.Kind() == reflect.Ptr {
+				if embedded.IsNil() {
+					break
+				}
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Ptr {
+				continue
+			}
+			if embedded.Kind() == reflect.Struct && !isScalar(embedded) {
+				if err := encodeStruct(values, embedded, scope); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if scope != "" {
+			name = scope + "[" + name + "]"
+		}
+		if opts.has("omitempty") && fv.IsZero() {
+			continue
+		}
+		if err := encodeField(values, name, fv, opts, field.Tag.Get("layout")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func encodeField(values url.Values, name string, fv reflect.Value, opts tagOptions, layout string) error {
+	if fv.Type().Implements(encoderType) {
+		if fv.Kind() == reflect.Ptr && fv.IsNil() {
+			return nil
+		}
+		return fv.Interface().(Encoder).EncodeValues(name, &values)
+	}
+	if fv.CanAddr() && reflect.PtrTo(fv.Type()).Implements(encoderType) {
+		return fv.Addr().Interface().(Encoder).EncodeValues(name, &values)
+	}
+	for fv.Kind() == reflect.Ptr {
+		if fv.IsNil() {
+			values.Add(name, "")
+			return nil
+		}
+		fv = fv.Elem()
+	}
+	if fv.Kind() == reflect.Slice && fv.Type().Elem().Kind() == reflect.Uint8 {
+		values.Add(name, string(fv.Bytes()))
+		return nil
+	}
+	if fv.Kind() == reflect.Slice || fv.Kind() == reflect.Array {
+		items := make([]string, 0, fv.Len())
+		for i := 0; i < fv.Len(); i++ {
+			s, err := formatValue(fv.Index(i), opts, layout)
+			if err != nil {
+				return err
+			}
+			items = append(items, s)
+		}
+		if opts.has("comma") {
+			values.Add(name, strings.Join(items, ","))
+			return nil
+		}
+		for _, item := range items {
+			values.Add(name, item)
+		}
+		return nil
+	}
+	if fv.Kind() == reflect.Struct && !isScalar(fv) {
+		return encodeStruct(values, fv, name)
+	}
+	s, err := formatValue(fv, opts, layout)
+	if err != nil {
+		return err
+	}
+	values.Add(name, s)
+	return nil
+}
+
+func isScalar(v reflect.Value) bool {
+	return v.Type() == timeType || v.Type().Implements(textMarshalerType) ||
+		reflect.PtrTo(v.Type()).Implements(textMarshalerType)
+}
+
+func formatValue(v reflect.Value, opts tagOptions, layout string) (string, error) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return "", nil
+		}
+		v = v.Elem()
+	}
+	if v.Type() == timeType {
+		t := v.Interface().(time.Time)
+		switch {
+		case opts.has("unix"):
+			return strconv.FormatInt(t.Unix(), 10), nil
+		case opts.has("unixmilli"):
+			return strconv.FormatInt(t.UnixNano()/int64(time.Millisecond), 10), nil
+		case layout != "":
+			return t.Format(layout), nil
+		}
+		return t.Format(time.RFC3339), nil
+	}
+	if m, ok := v.Interface().(encoding.TextMarshaler); ok {
+		text, err := m.MarshalText()
+		return string(text), err
+	}
+	if v.CanAddr() {
+		if m, ok := v.Addr().Interface().(encoding.TextMarshaler); ok {
+			text, err := m.MarshalText()
+			return string(text), err
+		}
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 32), nil
+	case reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64), nil
+	}
+	return fmt.Sprint(v.Interface()), nil
+}
+
+type tagOptions []string
+
+func parseTag(tag string) (string, tagOptions) {
+	parts := strings.Split(tag, ",")
+	return parts[0], parts[1:]
+}
+
+func (o tagOptions) has(option string) bool {
+	for _, opt := range o {
+		if opt == option {
+			return true
+		}
+	}
+	return false
+}
+
+// SetQueryStruct encodes v with EncodeStruct into the query string. Keys
+// already set are replaced.
+func (r *Request) SetQueryStruct(v interface{}) {
+	values, err := EncodeStruct(v)
+	if err != nil {
+		r.err = err
+		return
+	}
+	if r.query == nil {
+		r.query = url.Values{}
+	}
+	for key, value := range values {
+		r.query[key] = value
+	}
+}
+
+// SetFormStruct encodes v with EncodeStruct into the form fields. Keys
+// already set are replaced.
+func (r *Request) SetFormStruct(v interface{}) {
+	values, err := EncodeStruct(v)
+	if err != nil {
+		r.err = err
+		return
+	}
+	if r.form == nil {
+		r.form = url.Values{}
+	}
+	for key, value := range values {
+		r.form[key] = value
+	}
+}
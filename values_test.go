@@ -0,0 +1,124 @@
+/**
+* Created by GoLand.
+* User: luffy
+* Date: 2026-10-19
+* Time: 10:30
+ */
+package greq
+
+import (
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+type page struct {
+	Page int `url:"page,omitempty"`
+	Size int `url:"size"`
+}
+
+type sortOrder []string
+
+func (s sortOrder) EncodeValues(key string, v *url.Values) error {
+	v.Set(key, strings.Join(s, "|"))
+	return nil
+}
+
+type bounds struct {
+	Min, Max int
+}
+
+func (b *bounds) EncodeValues(key string, v *url.Values) error {
+	v.Set(key, strconv.Itoa(b.Min)+".."+strconv.Itoa(b.Max))
+	return nil
+}
+
+type level int
+
+func (l *level) MarshalText() ([]byte, error) {
+	return []byte("L" + strconv.Itoa(int(*l))), nil
+}
+
+type filter struct {
+	page
+	Name    string    `url:"name,omitempty"`
+	IDs     []int     `url:"id"`
+	Tags    []string  `url:"tags,comma"`
+	Since   time.Time `url:"since,unix"`
+	Day     time.Time `url:"day" layout:"2006-01-02"`
+	Sort    sortOrder `url:"sort"`
+	Range   bounds    `url:"range"`
+	Level   level     `url:"level"`
+	Owner   *struct{ ID string }
+	Secret  string `url:"-"`
+	private string
+}
+
+func TestEncodeStruct(t *testing.T) {
+	day := time.Date(2019, 5, 13, 11, 32, 0, 0, time.UTC)
+	f := filter{
+		page:    page{Size: 20},
+		IDs:     []int{1, 2},
+		Tags:    []string{"a", "b"},
+		Since:   day,
+		Day:     day,
+		Sort:    sortOrder{"name", "-id"},
+		Range:   bounds{Min: 1, Max: 9},
+		Level:   3,
+		Owner:   &struct{ ID string }{ID: "luffy"},
+		Secret:  "secret",
+		private: "private",
+	}
+	want := url.Values{
+		"size":      {"20"},
+		"id":        {"1", "2"},
+		"tags":      {"a,b"},
+		"since":     {"1557747120"},
+		"day":       {"2019-05-13"},
+		"sort":      {"name|-id"},
+		"range":     {"1..9"},
+		"level":     {"L3"},
+		"Owner[ID]": {"luffy"},
+	}
+	// Pointer receiver encoders must be used whether f is passed by pointer
+	// or by value.
+	for _, v := range []interface{}{&f, f} {
+		values, err := EncodeStruct(v)
+		if err != nil {
+			t.Fatalf("EncodeStruct error, err = %s", err.Error())
+		}
+		if !reflect.DeepEqual(values, want) {
+			t.Errorf("EncodeStruct(%T) got = %v, want = %v", v, values, want)
+		}
+	}
+}
+
+func TestQueryAndFormStruct(t *testing.T) {
+	ts := server(func(w http.ResponseWriter, r *http.Request) {
+		if v := r.URL.RawQuery; v != "page=2&size=10" {
+			t.Errorf("query got = %s, want = page=2&size=10", v)
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("r.ParseForm error, got = %s", err.Error())
+		}
+		if v := r.PostForm.Encode(); v != "id=1&id=2&name=luffy&size=0" {
+			t.Errorf("form got = %s, want = id=1&id=2&name=luffy&size=0", v)
+		}
+	})
+	defer ts.Close()
+
+	req := NewRequest("post", ts.URL)
+	req.SetQueryStruct(page{Page: 2, Size: 10})
+	req.SetFormStruct(struct {
+		page
+		Name string `url:"name"`
+		IDs  []int  `url:"id"`
+	}{Name: "luffy", IDs: []int{1, 2}})
+	if err := req.Exec().Error(); err != nil {
+		t.Errorf("req.exec error err= %s", err.Error())
+	}
+}